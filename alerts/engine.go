@@ -0,0 +1,254 @@
+package alerts
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gold-analyzer/config"
+	"gold-analyzer/i18n"
+	"gold-analyzer/model"
+)
+
+// Feed is a symbol/interval pair that rules need candles for
+type Feed struct {
+	Symbol   string
+	Interval string
+}
+
+// Trigger is a rule that fired on the current tick
+type Trigger struct {
+	Rule  Rule
+	Value float64
+	Price float64
+	Time  time.Time
+}
+
+// Message describes the trigger for console output and notifications, in
+// the current language
+func (t Trigger) Message() string {
+	return i18n.T("alerts.message", t.Rule.Name, t.Rule.Type, t.Rule.Interval, t.Value,
+		i18n.T("alerts.condition."+t.Rule.Condition), t.Rule.Value)
+}
+
+type ruleState struct {
+	seen   bool
+	active bool
+	last   float64
+	// fired is when the rule last fired, for its cooldown
+	fired time.Time
+	// firedCandle is the time of the last candle the rule fired on
+	firedCandle int64
+	// restored is set until the first evaluation after Restore
+	restored bool
+}
+
+// Engine evaluates alert rules and remembers their state between ticks,
+// so an alert fires once when its condition becomes true, not on every tick,
+// and not again within the cooldown of its rule.
+type Engine struct {
+	mu    sync.Mutex
+	rules []Rule
+	state []ruleState
+}
+
+// NewEngine creates an engine, defaulting empty rule symbols and intervals
+func NewEngine(rules []Rule, symbol, interval string) *Engine {
+	e := &Engine{
+		rules: make([]Rule, len(rules)),
+		state: make([]ruleState, len(rules)),
+	}
+	for i, r := range rules {
+		if r.Symbol == "" {
+			r.Symbol = symbol
+		}
+		if r.Interval == "" {
+			r.Interval = interval
+		}
+		e.rules[i] = r
+	}
+	return e
+}
+
+// Rules returns the configured rules
+func (e *Engine) Rules() []Rule {
+	return append([]Rule(nil), e.rules...)
+}
+
+// Feeds returns the distinct symbol/interval pairs used by the rules
+func (e *Engine) Feeds() []Feed {
+	var feeds []Feed
+	seen := make(map[Feed]bool)
+	for _, r := range e.rules {
+		f := Feed{Symbol: r.Symbol, Interval: r.Interval}
+		if !seen[f] {
+			seen[f] = true
+			feeds = append(feeds, f)
+		}
+	}
+	return feeds
+}
+
+// Bars returns the number of bars the rules of feed need
+func (e *Engine) Bars(feed Feed) int {
+	bars := 0
+	for _, r := range e.rules {
+		if r.Symbol == feed.Symbol && r.Interval == feed.Interval {
+			bars = max(bars, r.Bars())
+		}
+	}
+	return bars
+}
+
+// Range returns the longest FetchRange of the rules of feed
+func (e *Engine) Range(feed Feed) string {
+	var longest string
+	for _, r := range e.rules {
+		if r.Symbol == feed.Symbol && r.Interval == feed.Interval {
+			rg := r.FetchRange()
+			days, _ := config.RangeDays(rg)
+			if most, _ := config.RangeDays(longest); days > most {
+				longest = rg
+			}
+		}
+	}
+	return longest
+}
+
+// Evaluate checks every rule of the feed against candles and returns the
+// rules that fired. Rules that cannot be evaluated are reported as errors.
+func (e *Engine) Evaluate(feed Feed, candles []model.Candle) ([]Trigger, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var triggers []Trigger
+	var errs []error
+	now := time.Now()
+
+	for i, r := range e.rules {
+		if r.Symbol != feed.Symbol || r.Interval != feed.Interval {
+			continue
+		}
+
+		prev, cur, err := r.values(candles)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("alert %s: %w", r.Name, err))
+			continue
+		}
+
+		st := &e.state[i]
+		// Compare against the value seen on the previous tick once we have one
+		if st.seen {
+			prev = st.last
+		}
+		candle := candles[len(candles)-1].Time
+		// The previous run already alerted on this candle
+		replay := st.restored && !st.fired.IsZero() && candle <= st.firedCandle
+
+		var fire bool
+		switch r.Condition {
+		case CondAbove:
+			fire = cur > r.Value && !st.active
+			st.active = cur > r.Value
+		case CondBelow:
+			fire = cur < r.Value && !st.active
+			st.active = cur < r.Value
+		case CondCrossAbove:
+			fire = prev <= r.Value && cur > r.Value
+		case CondCrossBelow:
+			fire = prev >= r.Value && cur < r.Value
+		}
+		if replay || now.Sub(st.fired) < r.cooldown() {
+			fire = false
+		}
+		st.seen = true
+		st.last = cur
+		st.restored = false
+
+		if fire {
+			st.fired = now
+			st.firedCandle = candle
+			triggers = append(triggers, Trigger{
+				Rule:  r,
+				Value: cur,
+				Price: candles[len(candles)-1].Close,
+				Time:  now,
+			})
+		}
+	}
+
+	return triggers, errors.Join(errs...)
+}
+
+// RuleState is the remembered state of one rule, saved across restarts
+type RuleState struct {
+	// Rule identifies the rule; a state is only restored to an identical rule
+	Rule   string  `json:"rule"`
+	Active bool    `json:"active"`
+	Last   float64 `json:"last"`
+	// Fired is when the rule last fired; its cooldown runs from there
+	Fired time.Time `json:"fired,omitzero"`
+	// FiredCandle is the time of the last candle the rule fired on
+	FiredCandle time.Time `json:"fired_candle,omitzero"`
+}
+
+// key identifies a rule by every field that affects its evaluation
+func (r Rule) key() string {
+	return fmt.Sprintf("%s %s/%s %s(%d,%d) %s %g",
+		r.Name, r.Symbol, r.Interval, r.Type, r.Period, r.Lookback, r.Condition, r.Value)
+}
+
+// State returns the state of every rule evaluated at least once
+func (e *Engine) State() []RuleState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var states []RuleState
+	for i, st := range e.state {
+		if st.seen {
+			states = append(states, RuleState{
+				Rule:   e.rules[i].key(),
+				Active: st.active,
+				Last:   st.last,
+				Fired:  st.fired,
+			})
+			if !st.fired.IsZero() {
+				states[len(states)-1].FiredCandle = time.Unix(st.firedCandle, 0).UTC()
+			}
+		}
+	}
+	return states
+}
+
+// Restore applies saved rule states, so an alert that was active before a
+// restart, fired within its cooldown or fired on the same candle does not
+// fire again. States of rules that no longer exist or were
+// changed are ignored. It returns the number of restored rules.
+func (e *Engine) Restore(states []RuleState) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	saved := make(map[string]RuleState, len(states))
+	for _, s := range states {
+		saved[s.Rule] = s
+	}
+
+	n := 0
+	for i, r := range e.rules {
+		if s, ok := saved[r.key()]; ok {
+			e.state[i] = ruleState{
+				seen:     true,
+				active:   s.Active,
+				last:     s.Last,
+				fired:    s.Fired,
+				restored: true,
+			}
+			if !s.FiredCandle.IsZero() {
+				e.state[i].firedCandle = s.FiredCandle.Unix()
+			}
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,240 @@
+package alerts
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"gold-analyzer/config"
+	"gold-analyzer/indicators"
+	"gold-analyzer/model"
+)
+
+// Rule types
+const (
+	TypePrice    = "price"
+	TypeRSI      = "rsi"
+	TypeMACDHist = "macd_hist"
+	TypeATR      = "atr"
+	TypeATRRatio = "atr_ratio"
+)
+
+// Rule conditions. above/below fire whenever the condition becomes true,
+// cross_above/cross_below only fire on an actual crossing of the value.
+const (
+	CondAbove      = "above"
+	CondBelow      = "below"
+	CondCrossAbove = "cross_above"
+	CondCrossBelow = "cross_below"
+)
+
+// Rule is a user defined alert, evaluated on every tick
+type Rule struct {
+	// Name shown in the alert message
+	Name string `json:"name"`
+	// Symbol to watch (empty = configured symbol)
+	Symbol string `json:"symbol,omitempty"`
+	// Interval of the candles (empty = configured interval)
+	Interval string `json:"interval,omitempty"`
+	// Range of candles to fetch (empty = enough bars for the rule, see
+	// FetchRange)
+	Range string `json:"range,omitempty"`
+	// Type of value to watch: price, rsi, macd_hist, atr, atr_ratio
+	Type string `json:"type"`
+	// Condition: above, below, cross_above, cross_below
+	Condition string `json:"condition"`
+	// Value the watched value is compared against
+	Value float64 `json:"value"`
+	// Period for rsi/atr/atr_ratio (default 14)
+	Period int `json:"period,omitempty"`
+	// Lookback bars for the atr_ratio average (default 20)
+	Lookback int `json:"lookback,omitempty"`
+	// Cooldown is the minimum time between two alerts of the rule, like
+	// "30m" or "4h" (empty = no cooldown)
+	Cooldown string `json:"cooldown,omitempty"`
+}
+
+// LoadRules reads alert rules from a JSON file
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse alerts file %s: %w", path, err)
+	}
+
+	for i := range rules {
+		if err := rules[i].normalize(); err != nil {
+			return nil, fmt.Errorf("alert #%d (%s): %w", i+1, rules[i].Name, err)
+		}
+	}
+	return rules, nil
+}
+
+// normalize fills defaults and validates the rule
+func (r *Rule) normalize() error {
+	switch r.Type {
+	case TypePrice, TypeMACDHist:
+	case TypeRSI, TypeATR, TypeATRRatio:
+		if r.Period == 0 {
+			r.Period = 14
+		}
+		if r.Period < 1 {
+			return fmt.Errorf("period must be positive, got %d", r.Period)
+		}
+	default:
+		return fmt.Errorf("unknown type %q", r.Type)
+	}
+
+	if r.Type == TypeATRRatio {
+		if r.Lookback == 0 {
+			r.Lookback = 20
+		}
+		if r.Lookback < 1 {
+			return fmt.Errorf("lookback must be positive, got %d", r.Lookback)
+		}
+	}
+
+	if _, ok := config.IntervalDuration(r.Interval); r.Interval != "" && !ok {
+		return fmt.Errorf("interval %q: %s", r.Interval, config.IntervalHint)
+	}
+	if _, ok := config.RangeDays(r.Range); r.Range != "" && !ok {
+		return fmt.Errorf("range %q: %s", r.Range, config.RangeHint)
+	}
+
+	switch r.Condition {
+	case CondAbove, CondBelow, CondCrossAbove, CondCrossBelow:
+	default:
+		return fmt.Errorf("unknown condition %q", r.Condition)
+	}
+
+	if d, err := time.ParseDuration(r.Cooldown); r.Cooldown != "" && (err != nil || d < 0) {
+		return fmt.Errorf("cooldown %q: use a duration like 30m or 4h", r.Cooldown)
+	}
+
+	if r.Name == "" {
+		r.Name = fmt.Sprintf("%s %s %g", r.Type, r.Condition, r.Value)
+	}
+	return nil
+}
+
+// cooldown returns the parsed Cooldown; rules are validated on load
+func (r Rule) cooldown() time.Duration {
+	d, _ := time.ParseDuration(r.Cooldown)
+	return d
+}
+
+// Bars returns the number of bars the rule needs: the watched value on the
+// last two bars
+func (r Rule) Bars() int {
+	switch r.Type {
+	case TypeRSI:
+		return indicators.RSIValidFrom(r.Period) + 2
+	case TypeMACDHist:
+		return indicators.MACDValidFrom(indicators.MACDFast, indicators.MACDSlow, indicators.MACDSignal) + 2
+	case TypeATR:
+		return indicators.ATRValidFrom(r.Period) + 2
+	case TypeATRRatio:
+		return indicators.ATRValidFrom(r.Period) + r.Lookback + 2
+	}
+	return 2
+}
+
+// FetchRange returns the range of candles to fetch for the rule: its own
+// range, or enough days for Bars. Markets are closed at night, on weekends
+// and on holidays, so the bars are doubled and a few days added; intraday
+// ranges are capped at what Yahoo serves.
+func (r Rule) FetchRange() string {
+	if r.Range != "" {
+		return r.Range
+	}
+	// Rules are validated on load and given the feed interval
+	d, _ := config.IntervalDuration(r.Interval)
+	days := int((2*time.Duration(r.Bars())*d+24*time.Hour-1)/(24*time.Hour)) + 4
+	if limit, ok := config.IntradayDays(r.Interval); ok && days > limit {
+		days = limit
+	}
+	return strconv.Itoa(days) + "d"
+}
+
+// values returns the watched value on the last two bars
+func (r Rule) values(candles []model.Candle) (prev, cur float64, err error) {
+	n := len(candles)
+	if n < 2 {
+		return 0, 0, fmt.Errorf("need at least 2 bars, got %d", n)
+	}
+
+	closes := make([]float64, n)
+	highs := make([]float64, n)
+	lows := make([]float64, n)
+	for i, c := range candles {
+		closes[i] = c.Close
+		highs[i] = c.High
+		lows[i] = c.Low
+	}
+
+	var series []float64
+	switch r.Type {
+	case TypePrice:
+		series = closes
+	case TypeRSI:
+		if n <= r.Period+1 {
+			return 0, 0, fmt.Errorf("need %d bars for RSI(%d), got %d", r.Period+2, r.Period, n)
+		}
+		series, err = indicators.RSI(closes, r.Period)
+	case TypeMACDHist:
+		if from := indicators.MACDValidFrom(indicators.MACDFast, indicators.MACDSlow, indicators.MACDSignal); n <= from+1 {
+			return 0, 0, fmt.Errorf("need %d bars for the MACD histogram, got %d", from+2, n)
+		}
+		_, _, series, err = indicators.MACD(closes)
+	case TypeATR:
+		if n <= r.Period+1 {
+			return 0, 0, fmt.Errorf("need %d bars for ATR(%d), got %d", r.Period+2, r.Period, n)
+		}
+		series, err = indicators.ATR(highs, lows, closes, r.Period)
+	case TypeATRRatio:
+		if n <= r.Period+r.Lookback+1 {
+			return 0, 0, fmt.Errorf("need %d bars for ATR(%d) ratio over %d bars, got %d",
+				r.Period+r.Lookback+2, r.Period, r.Lookback, n)
+		}
+		var atr []float64
+		if atr, err = indicators.ATR(highs, lows, closes, r.Period); err == nil {
+			series = atrRatio(atr, r.Lookback)
+		}
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return series[n-2], series[n-1], nil
+}
+
+// atrRatio divides each ATR value by the average of the previous lookback
+// values; it is NaN until lookback ATR values are available
+func atrRatio(atr []float64, lookback int) []float64 {
+	ratio := make([]float64, len(atr))
+	for i := range ratio {
+		if i < lookback {
+			ratio[i] = math.NaN()
+			continue
+		}
+		var sum float64
+		for _, v := range atr[i-lookback : i] {
+			sum += v
+		}
+		avg := sum / float64(lookback)
+		switch {
+		case math.IsNaN(avg):
+			ratio[i] = math.NaN()
+		case avg > 0:
+			ratio[i] = atr[i] / avg
+		}
+	}
+	return ratio
+}
@@ -0,0 +1,123 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gold-analyzer/config"
+	"gold-analyzer/indicators"
+	"gold-analyzer/model"
+	"gold-analyzer/strategy"
+)
+
+// Result is the outcome of one analysis run for a symbol
+type Result struct {
+	Symbol        string          `json:"symbol"`
+	Interval      string          `json:"interval"`
+	Time          time.Time       `json:"time"`
+	CandleTime    time.Time       `json:"candle_time"`
+	Price         float64         `json:"price"`
+	Change        float64         `json:"change"`
+	ChangePercent float64         `json:"change_percent"`
+	Indicators    Indicators      `json:"indicators"`
+	Signal        strategy.Signal `json:"signal"`
+}
+
+// Indicators holds the last value of every indicator
+type Indicators struct {
+	RSIPeriod  int     `json:"rsi_period"`
+	RSI        float64 `json:"rsi"`
+	MACD       float64 `json:"macd"`
+	MACDSignal float64 `json:"macd_signal"`
+	MACDHist   float64 `json:"macd_hist"`
+	ATRPeriod  int     `json:"atr_period"`
+	ATR        float64 `json:"atr"`
+}
+
+// Analyze computes indicators and the strategy signal from candles. It
+// returns the context error if ctx is done before the work starts.
+func Analyze(ctx context.Context, cfg *config.Config, candles []model.Candle) (Result, error) {
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+	if len(candles) == 0 {
+		return Result{}, fmt.Errorf("no candles to analyze")
+	}
+	params := Params(cfg)
+	if len(candles) < params.MinBars {
+		return Result{}, fmt.Errorf("%w: need %d bars, got %d", strategy.ErrInsufficientHistory, params.MinBars, len(candles))
+	}
+
+	// استخراج داده‌ها
+	closes := make([]float64, len(candles))
+	highs := make([]float64, len(candles))
+	lows := make([]float64, len(candles))
+	for i, c := range candles {
+		closes[i] = c.Close
+		highs[i] = c.High
+		lows[i] = c.Low
+	}
+
+	// محاسبه اندیکاتورها
+	rsi, err := indicators.RSI(closes, cfg.RSIPeriod)
+	if err != nil {
+		return Result{}, err
+	}
+	macd, signal, hist, err := indicators.MACDPeriods(closes, cfg.MACDFastPeriod, cfg.MACDSlowPeriod, cfg.MACDSignalPeriod)
+	if err != nil {
+		return Result{}, err
+	}
+	atr, err := indicators.ATR(highs, lows, closes, cfg.ATRPeriod)
+	if err != nil {
+		return Result{}, err
+	}
+
+	last := len(closes) - 1
+	res := Result{
+		Symbol:     cfg.Symbol,
+		Interval:   cfg.Interval,
+		Time:       time.Now(),
+		CandleTime: time.Unix(candles[last].Time, 0),
+		Price:      closes[last],
+		Indicators: Indicators{
+			RSIPeriod:  cfg.RSIPeriod,
+			RSI:        rsi[last],
+			MACD:       macd[last],
+			MACDSignal: signal[last],
+			MACDHist:   hist[last],
+			ATRPeriod:  cfg.ATRPeriod,
+			ATR:        atr[last],
+		},
+	}
+
+	if last > 0 {
+		prev := closes[last-1]
+		res.Change = res.Price - prev
+		res.ChangePercent = (res.Change / prev) * 100
+	}
+
+	sig, err := strategy.Evaluate(params, rsi, hist, atr, res.Price)
+	if err != nil {
+		return Result{}, err
+	}
+	res.Signal = sig
+	return res, nil
+}
+
+// Params returns the strategy thresholds and required history of the
+// configuration
+func Params(cfg *config.Config) strategy.Params {
+	return strategy.Params{
+		RSIBuyLower:      cfg.RSIBuyLower,
+		RSIBuyUpper:      cfg.RSIBuyUpper,
+		RSISellThreshold: cfg.RSISellThreshold,
+		MinBars:          MinBars(cfg),
+	}
+}
+
+// MinBars is the number of candles the strategy needs with the indicator
+// periods of the configuration
+func MinBars(cfg *config.Config) int {
+	return strategy.MinBars(cfg.RSIPeriod, cfg.MACDFastPeriod, cfg.MACDSlowPeriod, cfg.MACDSignalPeriod, cfg.ATRPeriod)
+}
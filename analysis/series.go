@@ -0,0 +1,103 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gold-analyzer/config"
+	"gold-analyzer/indicators"
+	"gold-analyzer/model"
+	"gold-analyzer/strategy"
+)
+
+// Series holds indicator values and the strategy signal for every candle.
+// Indicator values before they are warmed up are NaN and signals empty.
+type Series struct {
+	Times    []time.Time
+	Closes   []float64
+	RSI      []float64
+	MACD     []float64
+	Signal   []float64
+	MACDHist []float64
+	ATR      []float64
+	Signals  []strategy.Signal
+	// WarmUp is the index of the first candle with a signal
+	WarmUp int
+}
+
+// Marker is a signal transition to BUY or SELL
+type Marker struct {
+	Index  int
+	Time   time.Time
+	Price  float64
+	Signal strategy.Signal
+}
+
+// BuildSeries computes indicators for all candles and replays the strategy
+// on every bar, using only data available at that bar.
+func BuildSeries(ctx context.Context, cfg *config.Config, candles []model.Candle) (Series, error) {
+	params := Params(cfg)
+	if len(candles) < params.MinBars {
+		return Series{}, fmt.Errorf("%w: need %d bars, got %d", strategy.ErrInsufficientHistory, params.MinBars, len(candles))
+	}
+	warmUp := params.MinBars - 1
+
+	rsi, err := indicators.NewRSIStream(cfg.RSIPeriod)
+	if err != nil {
+		return Series{}, err
+	}
+	macd, err := indicators.NewMACDStream(cfg.MACDFastPeriod, cfg.MACDSlowPeriod, cfg.MACDSignalPeriod)
+	if err != nil {
+		return Series{}, err
+	}
+	atr, err := indicators.NewATRStream(cfg.ATRPeriod)
+	if err != nil {
+		return Series{}, err
+	}
+
+	n := len(candles)
+	s := Series{
+		Times:    make([]time.Time, n),
+		Closes:   make([]float64, n),
+		RSI:      make([]float64, n),
+		MACD:     make([]float64, n),
+		Signal:   make([]float64, n),
+		MACDHist: make([]float64, n),
+		ATR:      make([]float64, n),
+		Signals:  make([]strategy.Signal, n),
+		WarmUp:   warmUp,
+	}
+	// The streams match the batch indicators bar for bar in a single pass
+	for i, c := range candles {
+		s.Times[i] = time.Unix(c.Time, 0)
+		s.Closes[i] = c.Close
+		s.RSI[i] = rsi.Update(c.Close)
+		s.MACD[i], s.Signal[i], s.MACDHist[i] = macd.Update(c.Close)
+		s.ATR[i] = atr.Update(c.High, c.Low, c.Close)
+	}
+
+	// Every indicator is causal, so a prefix equals a live run at that bar
+	for i := warmUp; i < n; i++ {
+		// Replaying years of bars takes a while; stop early on shutdown
+		if i%1024 == 0 && ctx.Err() != nil {
+			return Series{}, ctx.Err()
+		}
+		s.Signals[i] = strategy.GoldStrategyWith(params, s.RSI[:i+1], s.MACDHist[:i+1], s.ATR[:i+1], s.Closes[i])
+	}
+	return s, nil
+}
+
+// Markers returns the bars where the signal changed to BUY or SELL
+func (s Series) Markers() []Marker {
+	var markers []Marker
+	var prev strategy.Signal
+	for i := s.WarmUp; i < len(s.Signals); i++ {
+		sig := s.Signals[i]
+		if sig != prev && sig != strategy.HOLD {
+			markers = append(markers, Marker{Index: i, Time: s.Times[i], Price: s.Closes[i], Signal: sig})
+		}
+		prev = sig
+	}
+	return markers
+}
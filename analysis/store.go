@@ -0,0 +1,152 @@
+package analysis
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"gold-analyzer/strategy"
+)
+
+// SignalChange is an entry of the signal history
+type SignalChange struct {
+	Time     time.Time       `json:"time"`
+	Previous strategy.Signal `json:"previous,omitempty"`
+	Signal   strategy.Signal `json:"signal"`
+	Price    float64         `json:"price"`
+}
+
+// Event types published to subscribers
+const (
+	EventAnalysis = "analysis"
+	EventSignal   = "signal"
+)
+
+// Event is published to subscribers for every result and signal transition
+type Event struct {
+	Type   string        `json:"type"`
+	Symbol string        `json:"symbol"`
+	Result *Result       `json:"result,omitempty"`
+	Change *SignalChange `json:"change,omitempty"`
+}
+
+// Store keeps the latest result and the signal history of every symbol
+type Store struct {
+	mu          sync.RWMutex
+	limit       int
+	latest      map[string]Result
+	history     map[string][]SignalChange
+	subscribers map[chan Event]struct{}
+}
+
+// NewStore creates a store keeping at most limit history entries per symbol
+func NewStore(limit int) *Store {
+	return &Store{
+		limit:       limit,
+		latest:      make(map[string]Result),
+		history:     make(map[string][]SignalChange),
+		subscribers: make(map[chan Event]struct{}),
+	}
+}
+
+// Record stores a result and appends to the history if the signal changed.
+// It returns the history entry when there was a transition.
+func (s *Store) Record(r Result) (SignalChange, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev, ok := s.latest[r.Symbol]
+	s.latest[r.Symbol] = r
+	s.publishLocked(Event{Type: EventAnalysis, Symbol: r.Symbol, Result: &r})
+
+	if ok && prev.Signal == r.Signal {
+		return SignalChange{}, false
+	}
+
+	change := SignalChange{Time: r.Time, Previous: prev.Signal, Signal: r.Signal, Price: r.Price}
+	h := append(s.history[r.Symbol], change)
+	if s.limit > 0 && len(h) > s.limit {
+		h = h[len(h)-s.limit:]
+	}
+	s.history[r.Symbol] = h
+	s.publishLocked(Event{Type: EventSignal, Symbol: r.Symbol, Change: &change})
+	return change, true
+}
+
+// Subscribe returns a channel receiving every new event and a function to
+// unsubscribe. Events are dropped for subscribers that fall behind.
+func (s *Store) Subscribe(buffer int) (<-chan Event, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.subscribeLocked(buffer)
+}
+
+func (s *Store) subscribeLocked(buffer int) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+	s.subscribers[ch] = struct{}{}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.subscribers, ch)
+			s.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// SubscribeLatest is Subscribe that also returns the latest result of
+// every symbol, sorted by symbol. No event is both in the results and on
+// the channel.
+func (s *Store) SubscribeLatest(buffer int) ([]Result, <-chan Event, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	latest := make([]Result, 0, len(s.latest))
+	for _, sym := range s.symbolsLocked() {
+		latest = append(latest, s.latest[sym])
+	}
+	ch, unsubscribe := s.subscribeLocked(buffer)
+	return latest, ch, unsubscribe
+}
+
+func (s *Store) publishLocked(e Event) {
+	for ch := range s.subscribers {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Latest returns the latest result for symbol
+func (s *Store) Latest(symbol string) (Result, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r, ok := s.latest[symbol]
+	return r, ok
+}
+
+// History returns the signal history for symbol, oldest first
+func (s *Store) History(symbol string) []SignalChange {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]SignalChange(nil), s.history[symbol]...)
+}
+
+// Symbols returns the symbols that have results, sorted
+func (s *Store) Symbols() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.symbolsLocked()
+}
+
+func (s *Store) symbolsLocked() []string {
+	symbols := make([]string, 0, len(s.latest))
+	for sym := range s.latest {
+		symbols = append(symbols, sym)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"gold-analyzer/health"
+)
+
+// Runner reports whether the application still accepts work.
+// shutdown.Manager implements it.
+type Runner interface {
+	IsRunning() bool
+}
+
+type healthResponse struct {
+	Status    string `json:"status"`
+	Running   bool   `json:"running"`
+	Staleness string `json:"staleness,omitempty"`
+	MaxStale  string `json:"max_staleness"`
+	// Fetch is the status of the worst symbol, Symbols of every symbol
+	Fetch   health.Status   `json:"fetch"`
+	Symbols []health.Status `json:"symbols,omitempty"`
+}
+
+// SetHealth wires the fetch tracker and the running state into /healthz and /readyz
+func (s *Server) SetHealth(tracker *health.Tracker, runner Runner) {
+	s.tracker = tracker
+	s.runner = runner
+}
+
+// maxStaleness is how old data may get before the analyzer is considered stuck
+func (s *Server) maxStaleness() time.Duration {
+	cfg := s.cfg.Load()
+	if cfg.HealthMaxStaleness > 0 {
+		return cfg.HealthMaxStaleness
+	}
+	// Allow a few missed ticks plus the Yahoo retry budget
+	return 3*cfg.CheckInterval + time.Minute
+}
+
+func (s *Server) healthStatus() (healthResponse, health.Status) {
+	var st health.Status
+	var symbols []health.Status
+	if s.tracker != nil {
+		st = s.tracker.Status()
+		symbols = s.tracker.Symbols()
+	}
+
+	resp := healthResponse{
+		Running:  (s.runner == nil || s.runner.IsRunning()) && !s.draining.Load(),
+		MaxStale: s.maxStaleness().String(),
+		Fetch:    st,
+		Symbols:  symbols,
+	}
+	if d := st.Staleness(time.Now()); d > 0 {
+		resp.Staleness = d.Round(time.Second).String()
+	}
+	return resp, st
+}
+
+// handleHealthz is the liveness probe: it fails when the monitoring loop
+// stopped making progress so the orchestrator restarts the container.
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	resp, st := s.healthStatus()
+
+	if s.tracker != nil && !st.Live(time.Now(), s.maxStaleness()) {
+		resp.Status = "stuck"
+		writeJSON(w, http.StatusServiceUnavailable, resp)
+		return
+	}
+
+	resp.Status = "ok"
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleReadyz is the readiness probe: it fails during graceful shutdown
+// and while there is no fresh data to serve.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	resp, st := s.healthStatus()
+
+	switch {
+	case !resp.Running:
+		resp.Status = "shutting_down"
+	case s.tracker != nil && !st.Fresh(time.Now(), s.maxStaleness()):
+		resp.Status = "stale"
+	default:
+		resp.Status = "ready"
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	writeJSON(w, http.StatusServiceUnavailable, resp)
+}
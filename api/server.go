@@ -0,0 +1,212 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"gold-analyzer/analysis"
+	"gold-analyzer/config"
+	"gold-analyzer/health"
+	"gold-analyzer/metrics"
+)
+
+// Server exposes the latest analysis over HTTP as JSON
+type Server struct {
+	cfg   atomic.Pointer[config.Config]
+	store *analysis.Store
+	mux   *http.ServeMux
+	http  *http.Server
+	done  chan struct{}
+
+	tracker  *health.Tracker
+	runner   Runner
+	draining atomic.Bool
+}
+
+// NewServer creates an API server listening on addr
+func NewServer(addr string, cfg *config.Config, store *analysis.Store) *Server {
+	s := &Server{
+		store: store,
+		mux:   http.NewServeMux(),
+		done:  make(chan struct{}),
+	}
+	s.cfg.Store(cfg)
+
+	s.mux.HandleFunc("GET /api/symbols", s.handleSymbols)
+	s.mux.HandleFunc("GET /api/symbols/{symbol}", s.handleLatest)
+	s.mux.HandleFunc("GET /api/symbols/{symbol}/price", s.handlePrice)
+	s.mux.HandleFunc("GET /api/symbols/{symbol}/indicators", s.handleIndicators)
+	s.mux.HandleFunc("GET /api/symbols/{symbol}/signal", s.handleSignal)
+	s.mux.HandleFunc("GET /api/symbols/{symbol}/history", s.handleHistory)
+	s.mux.HandleFunc("GET /api/symbols/{symbol}/config", s.handleConfig)
+	s.mux.HandleFunc("GET /api/stream", s.handleStream)
+	s.mux.Handle("GET /metrics", metrics.Default.Handler())
+	s.mux.HandleFunc("GET /healthz", s.handleHealthz)
+	s.mux.HandleFunc("GET /readyz", s.handleReadyz)
+
+	s.http = &http.Server{
+		Addr:              addr,
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	// Long-lived streams are not idle, so end them explicitly on shutdown
+	s.http.RegisterOnShutdown(func() { close(s.done) })
+	return s
+}
+
+// SetConfig replaces the configuration after a reload
+func (s *Server) SetConfig(cfg *config.Config) {
+	s.cfg.Store(cfg)
+}
+
+// Handler returns the HTTP handler, useful for tests
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// Start serves in the background; errors other than a clean close are sent on the returned channel
+func (s *Server) Start() <-chan error {
+	errCh := make(chan error, 1)
+	go func() {
+		if err := s.http.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+	return errCh
+}
+
+// Drain makes /readyz report shutting_down and waits d, or until ctx is
+// done, so load balancers see it and stop sending requests before Shutdown
+// closes the listener
+func (s *Server) Drain(ctx context.Context, d time.Duration) error {
+	s.draining.Store(true)
+	select {
+	case <-time.After(d):
+	case <-ctx.Done():
+	}
+	return nil
+}
+
+// Shutdown stops accepting requests and waits for in-flight ones
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.http.Shutdown(ctx)
+}
+
+type priceResponse struct {
+	Symbol        string    `json:"symbol"`
+	Time          time.Time `json:"time"`
+	Price         float64   `json:"price"`
+	Change        float64   `json:"change"`
+	ChangePercent float64   `json:"change_percent"`
+}
+
+type signalResponse struct {
+	Symbol string    `json:"symbol"`
+	Time   time.Time `json:"time"`
+	Signal string    `json:"signal"`
+	Price  float64   `json:"price"`
+}
+
+type configResponse struct {
+	Symbol           string  `json:"symbol"`
+	Interval         string  `json:"interval"`
+	Range            string  `json:"range"`
+	CheckInterval    string  `json:"check_interval"`
+	RSIPeriod        int     `json:"rsi_period"`
+	ATRPeriod        int     `json:"atr_period"`
+	RSIBuyLower      float64 `json:"rsi_buy_lower"`
+	RSIBuyUpper      float64 `json:"rsi_buy_upper"`
+	RSISellThreshold float64 `json:"rsi_sell_threshold"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (s *Server) handleSymbols(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.store.Symbols())
+}
+
+func (s *Server) handleLatest(w http.ResponseWriter, r *http.Request) {
+	if res, ok := s.latest(w, r); ok {
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func (s *Server) handlePrice(w http.ResponseWriter, r *http.Request) {
+	if res, ok := s.latest(w, r); ok {
+		writeJSON(w, http.StatusOK, priceResponse{
+			Symbol:        res.Symbol,
+			Time:          res.CandleTime,
+			Price:         res.Price,
+			Change:        res.Change,
+			ChangePercent: res.ChangePercent,
+		})
+	}
+}
+
+func (s *Server) handleIndicators(w http.ResponseWriter, r *http.Request) {
+	if res, ok := s.latest(w, r); ok {
+		writeJSON(w, http.StatusOK, res.Indicators)
+	}
+}
+
+func (s *Server) handleSignal(w http.ResponseWriter, r *http.Request) {
+	if res, ok := s.latest(w, r); ok {
+		writeJSON(w, http.StatusOK, signalResponse{
+			Symbol: res.Symbol,
+			Time:   res.Time,
+			Signal: string(res.Signal),
+			Price:  res.Price,
+		})
+	}
+}
+
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.latest(w, r); ok {
+		writeJSON(w, http.StatusOK, s.store.History(r.PathValue("symbol")))
+	}
+}
+
+func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
+	symbol := r.PathValue("symbol")
+	for _, cfg := range s.cfg.Load().Targets() {
+		if cfg.Symbol != symbol {
+			continue
+		}
+		writeJSON(w, http.StatusOK, configResponse{
+			Symbol:           cfg.Symbol,
+			Interval:         cfg.Interval,
+			Range:            cfg.Range,
+			CheckInterval:    cfg.CheckInterval.String(),
+			RSIPeriod:        cfg.RSIPeriod,
+			ATRPeriod:        cfg.ATRPeriod,
+			RSIBuyLower:      cfg.RSIBuyLower,
+			RSIBuyUpper:      cfg.RSIBuyUpper,
+			RSISellThreshold: cfg.RSISellThreshold,
+		})
+		return
+	}
+	writeJSON(w, http.StatusNotFound, errorResponse{Error: "unknown symbol " + symbol})
+}
+
+// latest looks up the symbol of the request and writes a 404 if it has no result yet
+func (s *Server) latest(w http.ResponseWriter, r *http.Request) (analysis.Result, bool) {
+	symbol := r.PathValue("symbol")
+	res, ok := s.store.Latest(symbol)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "no analysis for symbol " + symbol})
+	}
+	return res, ok
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// keepAliveInterval keeps idle SSE connections open through proxies
+const keepAliveInterval = 15 * time.Second
+
+// handleStream pushes analysis results and signal transitions as
+// Server-Sent Events. An optional ?symbol= query filters the stream.
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: "streaming not supported"})
+		return
+	}
+
+	symbol := r.URL.Query().Get("symbol")
+	// The current state and the subscription are taken together, so a
+	// result recorded meanwhile is sent once
+	latest, events, unsubscribe := s.store.SubscribeLatest(16)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	// Send the current state first so clients do not wait for the next tick
+	for _, res := range latest {
+		if symbol == "" || res.Symbol == symbol {
+			writeEvent(w, "analysis", res)
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			if symbol != "" && e.Symbol != symbol {
+				continue
+			}
+			if e.Result != nil {
+				writeEvent(w, e.Type, e.Result)
+			} else {
+				writeEvent(w, e.Type, e.Change)
+			}
+			flusher.Flush()
+
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+
+		case <-r.Context().Done():
+			return
+
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
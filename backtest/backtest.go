@@ -0,0 +1,103 @@
+// Package backtest replays the strategy over historical candles with a
+// simple long-only paper trading model: a BUY opens a position at the
+// close, a SELL closes it at the close. Fees and slippage are ignored.
+package backtest
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"gold-analyzer/analysis"
+	"gold-analyzer/strategy"
+)
+
+// Trade is one paper position
+type Trade struct {
+	EntryTime  time.Time `json:"entry_time"`
+	EntryPrice float64   `json:"entry_price"`
+	ExitTime   time.Time `json:"exit_time,omitzero"`
+	ExitPrice  float64   `json:"exit_price,omitempty"`
+	// Return is the fractional gain, marked to the last close while open
+	Return float64 `json:"return"`
+	Open   bool    `json:"open,omitempty"`
+}
+
+// Close exits the position at price
+func (t *Trade) Close(at time.Time, price float64) {
+	t.ExitTime = at
+	t.ExitPrice = price
+	t.Return = price/t.EntryPrice - 1
+}
+
+// Result summarizes a backtest
+type Result struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Bars   int       `json:"bars"`
+	Trades []Trade   `json:"trades"`
+	// TotalReturn compounds every trade, including an open one
+	TotalReturn float64 `json:"total_return"`
+	// BuyAndHold is the return of holding from the first signal bar to the end
+	BuyAndHold  float64 `json:"buy_and_hold"`
+	WinRate     float64 `json:"win_rate"`
+	MaxDrawdown float64 `json:"max_drawdown"`
+}
+
+// Run trades the signals of s
+func Run(s analysis.Series) (Result, error) {
+	first, last := s.WarmUp, len(s.Closes)-1
+	if last < first {
+		return Result{}, fmt.Errorf("series has no bars after warm-up")
+	}
+
+	res := Result{
+		Start:      s.Times[first],
+		End:        s.Times[last],
+		Bars:       last - first + 1,
+		Trades:     []Trade{},
+		BuyAndHold: s.Closes[last]/s.Closes[first] - 1,
+	}
+
+	var open *Trade
+	equity, peak := 1.0, 1.0
+	closed := 1.0
+	for i := first; i <= last; i++ {
+		price := s.Closes[i]
+		switch {
+		case s.Signals[i] == strategy.BUY && open == nil:
+			open = &Trade{EntryTime: s.Times[i], EntryPrice: price}
+		case s.Signals[i] == strategy.SELL && open != nil:
+			open.Close(s.Times[i], price)
+			closed *= 1 + open.Return
+			res.Trades = append(res.Trades, *open)
+			open = nil
+		}
+
+		// Mark to market for the drawdown
+		equity = closed
+		if open != nil {
+			equity *= price / open.EntryPrice
+		}
+		peak = math.Max(peak, equity)
+		res.MaxDrawdown = math.Max(res.MaxDrawdown, 1-equity/peak)
+	}
+
+	var wins, count int
+	for _, t := range res.Trades {
+		count++
+		if t.Return > 0 {
+			wins++
+		}
+	}
+	if open != nil {
+		open.Return = s.Closes[last]/open.EntryPrice - 1
+		open.Open = true
+		res.Trades = append(res.Trades, *open)
+	}
+	if count > 0 {
+		res.WinRate = float64(wins) / float64(count)
+	}
+	res.TotalReturn = equity - 1
+	return res, nil
+}
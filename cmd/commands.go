@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gold-analyzer/analysis"
+	"gold-analyzer/backtest"
+	"gold-analyzer/config"
+	"gold-analyzer/i18n"
+	"gold-analyzer/model"
+	"gold-analyzer/report"
+	"gold-analyzer/yahoo"
+)
+
+// analyzeCommand analyzes every watchlist symbol once; it fails when any
+// symbol produced no result
+func analyzeCommand(fs *flag.FlagSet, cfg *config.Config) func(context.Context) int {
+	fs.StringVar(&cfg.OutputFormat, "output", cfg.OutputFormat, "console, json, ndjson or csv (OUTPUT_FORMAT)")
+
+	return func(ctx context.Context) int {
+		if err := setupOutput(cfg, false); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitUsage
+		}
+		code := exitOK
+		for _, target := range cfg.Targets() {
+			if err := analyzeGold(ctx, target); err != nil {
+				code = exitFailure
+			}
+		}
+		return code
+	}
+}
+
+func watchCommand(fs *flag.FlagSet, cfg *config.Config) func(context.Context) int {
+	watchFlags(fs, cfg)
+	// The loop stops through the shutdown manager's context
+	return func(context.Context) int {
+		if err := setupOutput(cfg, true); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitUsage
+		}
+		return watch(cfg, false)
+	}
+}
+
+func serveCommand(fs *flag.FlagSet, cfg *config.Config) func(context.Context) int {
+	watchFlags(fs, cfg)
+	fs.StringVar(&cfg.HTTPAddr, "addr", cfg.HTTPAddr, "HTTP listen address (HTTP_ADDR)")
+	return func(context.Context) int {
+		if err := setupOutput(cfg, true); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitUsage
+		}
+		return watch(cfg, true)
+	}
+}
+
+// watchFlags registers the flags shared by watch and serve
+func watchFlags(fs *flag.FlagSet, cfg *config.Config) {
+	fs.StringVar(&cfg.OutputFormat, "output", cfg.OutputFormat, "console, json, ndjson, csv or tui (OUTPUT_FORMAT)")
+	fs.DurationVar(&cfg.CheckInterval, "check-interval", cfg.CheckInterval, "time between analyses (CHECK_INTERVAL_MINUTES)")
+	fs.StringVar(&cfg.AlertsFile, "alerts", cfg.AlertsFile, "alert rules file (ALERTS_FILE)")
+	fs.StringVar(&cfg.StateFile, "state", cfg.StateFile, "runtime state file kept across restarts (STATE_FILE)")
+}
+
+// fetchCommand exports candles to a file or stdout
+func fetchCommand(fs *flag.FlagSet, cfg *config.Config) func(context.Context) int {
+	format := fs.String("format", "csv", "csv or json")
+	out := fs.String("out", "", "output file (default stdout)")
+
+	return func(ctx context.Context) int {
+		*format = strings.ToLower(*format)
+		if *format != "csv" && *format != "json" {
+			fmt.Fprintf(os.Stderr, "unknown format %q (use csv or json)\n", *format)
+			return exitUsage
+		}
+
+		// One-shot commands cover the first watchlist symbol
+		cfg := cfg.Targets()[0]
+		candles, err := fetch(ctx, cfg)
+		if err != nil {
+			return exitFailure
+		}
+
+		err = writeTo(*out, func(w io.Writer) error {
+			if *format == "json" {
+				enc := json.NewEncoder(w)
+				enc.SetIndent("", "  ")
+				return enc.Encode(candles)
+			}
+			return writeCandlesCSV(w, candles)
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("fetch.failed", err))
+			return exitFailure
+		}
+
+		if *out != "" {
+			fmt.Fprintln(os.Stderr, i18n.T("fetch.written", len(candles), *out))
+		}
+		slog.Info("candles exported", "symbol", cfg.Symbol, "interval", cfg.Interval, "count", len(candles), "file", *out)
+		return exitOK
+	}
+}
+
+func writeCandlesCSV(w io.Writer, candles []model.Candle) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"time", "open", "high", "low", "close", "volume"})
+	for _, c := range candles {
+		cw.Write([]string{
+			time.Unix(c.Time, 0).UTC().Format(time.RFC3339),
+			strconv.FormatFloat(c.Open, 'f', -1, 64),
+			strconv.FormatFloat(c.High, 'f', -1, 64),
+			strconv.FormatFloat(c.Low, 'f', -1, 64),
+			strconv.FormatFloat(c.Close, 'f', -1, 64),
+			strconv.FormatInt(c.Volume, 10),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// backtestCommand replays the strategy over the fetched history
+func backtestCommand(fs *flag.FlagSet, cfg *config.Config) func(context.Context) int {
+	format := fs.String("format", "text", "text or json")
+
+	return func(ctx context.Context) int {
+		if *format != "text" && *format != "json" {
+			fmt.Fprintf(os.Stderr, "unknown format %q (use text or json)\n", *format)
+			return exitUsage
+		}
+
+		cfg := cfg.Targets()[0]
+		candles, err := fetch(ctx, cfg)
+		if err != nil {
+			return exitFailure
+		}
+		series, err := analysis.BuildSeries(ctx, cfg, candles)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("backtest.failed", err))
+			return exitFailure
+		}
+		res, err := backtest.Run(series)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("backtest.failed", err))
+			return exitFailure
+		}
+
+		slog.Info("backtest",
+			"symbol", cfg.Symbol,
+			"interval", cfg.Interval,
+			"range", cfg.Range,
+			"trades", len(res.Trades),
+			"total_return", res.TotalReturn,
+			"max_drawdown", res.MaxDrawdown,
+		)
+
+		if *format == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(res); err != nil {
+				return exitFailure
+			}
+			return exitOK
+		}
+		printBacktest(cfg, res)
+		return exitOK
+	}
+}
+
+func printBacktest(cfg *config.Config, res backtest.Result) {
+	fmt.Println(i18n.T("backtest.header", cfg.Symbol, cfg.Interval, cfg.Range))
+	fmt.Println(strings.Repeat("=", 70))
+	fmt.Println(i18n.T("backtest.period", i18n.Time(res.Start), i18n.Time(res.End), res.Bars))
+	for _, t := range res.Trades {
+		if t.Open {
+			fmt.Println(i18n.T("backtest.open_trade", i18n.Time(t.EntryTime), t.EntryPrice, t.Return*100))
+			continue
+		}
+		fmt.Println(i18n.T("backtest.trade", i18n.Time(t.EntryTime), t.EntryPrice, i18n.Time(t.ExitTime), t.ExitPrice, t.Return*100))
+	}
+	fmt.Println(strings.Repeat("-", 70))
+	fmt.Println(i18n.T("backtest.trades", len(res.Trades)))
+	fmt.Println(i18n.T("backtest.total_return", res.TotalReturn*100))
+	fmt.Println(i18n.T("backtest.buy_hold", res.BuyAndHold*100))
+	fmt.Println(i18n.T("backtest.win_rate", res.WinRate*100))
+	fmt.Println(i18n.T("backtest.max_drawdown", res.MaxDrawdown*100))
+}
+
+// reportCommand fetches candles for the chosen period and writes the HTML report
+func reportCommand(fs *flag.FlagSet, cfg *config.Config) func(context.Context) int {
+	out := fs.String("out", "report.html", "output file")
+
+	return func(ctx context.Context) int {
+		cfg := cfg.Targets()[0]
+		candles, err := fetch(ctx, cfg)
+		if err != nil {
+			return exitFailure
+		}
+
+		series, err := analysis.BuildSeries(ctx, cfg, candles)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("report.failed", err))
+			return exitFailure
+		}
+
+		err = writeTo(*out, func(w io.Writer) error {
+			return report.Write(w, cfg, series, time.Now())
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("report.failed", err))
+			return exitFailure
+		}
+
+		fmt.Println(i18n.T("report.written", *out))
+		slog.Info("report written", "file", *out, "symbol", cfg.Symbol, "interval", cfg.Interval, "range", cfg.Range)
+		return exitOK
+	}
+}
+
+// configCommand prints the effective configuration and the source of
+// every value: the default, the config file, an environment variable or a flag
+func configCommand(fs *flag.FlagSet, cfg *config.Config) func(context.Context) int {
+	return func(ctx context.Context) int {
+		if fs.Arg(0) != "print" {
+			fmt.Fprintln(os.Stderr, "usage: analyzer config print [flags]")
+			return exitUsage
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+		for _, s := range cfg.Settings() {
+			value := displayValue(s.Key, s.Value)
+			if s.Key == "watchlist" && value != "" {
+				// Per-symbol settings are listed below
+				symbols := make([]string, len(cfg.Watchlist))
+				for i, w := range cfg.Watchlist {
+					symbols[i] = w.Symbol
+				}
+				value = strings.Join(symbols, ",")
+			}
+			if value == "" {
+				value = `""`
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", s.Key, value, cfg.Source(s.Key))
+		}
+		tw.Flush()
+
+		if len(cfg.Watchlist) > 0 {
+			fmt.Println()
+			fmt.Fprintln(tw, "SYMBOL\tINTERVAL\tRANGE\tEVERY\tRSI\tMACD\tATR\tBUY\tSELL")
+			for _, t := range cfg.Targets() {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%v\t%d\t%d/%d/%d\t%d\t%g-%g\t>%g\n",
+					t.Symbol, t.Interval, t.Range, t.CheckInterval, t.RSIPeriod,
+					t.MACDFastPeriod, t.MACDSlowPeriod, t.MACDSignalPeriod, t.ATRPeriod,
+					t.RSIBuyLower, t.RSIBuyUpper, t.RSISellThreshold)
+			}
+			tw.Flush()
+		}
+
+		if err := cfg.Validate(); err != nil {
+			fmt.Println()
+			printInvalid(os.Stderr, err)
+			return exitUsage
+		}
+		return exitOK
+	}
+}
+
+// fetch downloads candles for the one-shot commands and reports failures
+func fetch(ctx context.Context, cfg *config.Config) ([]model.Candle, error) {
+	candles, err := yahoo.FetchCandles(ctx, cfg.Symbol, cfg.Interval, cfg.Range)
+	if err == nil && len(candles) == 0 {
+		err = errors.New("no data")
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("analysis.fetch_failed", err))
+		slog.Error("fetch failed", "symbol", cfg.Symbol, "interval", cfg.Interval, "error", err)
+	}
+	return candles, err
+}
+
+// writeTo writes to path, or to stdout when path is empty
+func writeTo(path string, write func(io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	return errors.Join(write(f), f.Close())
+}
@@ -1,274 +1,268 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"os/signal"
 	"strings"
-	"time"
+	"syscall"
 
 	"gold-analyzer/config"
-	"gold-analyzer/indicators"
+	"gold-analyzer/i18n"
+	"gold-analyzer/logging"
+	"gold-analyzer/output"
 	"gold-analyzer/shutdown"
-	"gold-analyzer/strategy"
-	"gold-analyzer/yahoo"
+	"gold-analyzer/tui"
 )
 
-var lastSignal strategy.Signal
-
-func main() {
-	cfg := config.DefaultConfig()
-
-	fmt.Println("🚀 Gold Analyzer - شروع نظارت خودکار...")
-	fmt.Println(strings.Repeat("=", 70))
-	fmt.Printf("⚙️  تنظیمات:\n")
-	fmt.Printf("   • نماد: %s\n", cfg.Symbol)
-	fmt.Printf("   • بازه زمانی: %s\n", cfg.Interval)
-	fmt.Printf("   • محدوده: %s\n", cfg.Range)
-	fmt.Printf("   • فاصله بررسی: %v\n", cfg.CheckInterval)
-	fmt.Println(strings.Repeat("=", 70))
-	fmt.Println("💡 برای متوقف کردن، Ctrl+C را فشار دهید...")
-
-	// Create shutdown manager
-	shutdownMgr := shutdown.NewManager()
-
-	// Register shutdown hooks
-	shutdownMgr.RegisterHook(func() error {
-		return saveShutdownStats(cfg)
-	})
-
-	shutdownMgr.RegisterHook(func() error {
-		return closeResources(cfg)
-	})
-
-	// Start signal handling
-	shutdownMgr.Start()
-
-	// Handle shutdown signal in a separate goroutine
-	go func() {
-		sig := shutdownMgr.WaitForShutdown()
-		fmt.Printf("\n\n🛑 سیگنال دریافت شد: %v\n", sig)
-		fmt.Println("⏳ درحال متوقف کردن برنامه...")
-		shutdownMgr.Stop()
-	}()
-
-	// Create ticker for periodic checks
-	ticker := time.NewTicker(cfg.CheckInterval)
-	defer ticker.Stop()
-
-	// اجرای اولی بدون تاخیر
-	analyzeGold(cfg)
+// Exit codes for scripting
+const (
+	exitOK      = 0
+	exitFailure = 1                   // fetch, analysis or I/O failure
+	exitUsage   = 2                   // unknown command, bad flag or invalid configuration
+	exitForced  = shutdown.ExitForced // second signal during the shutdown
+)
 
-	// حلقه نظارت
-	for {
-		if !shutdownMgr.IsRunning() {
-			// Perform graceful shutdown
-			if err := shutdownMgr.Shutdown(cfg.ShutdownTimeout); err != nil {
-				fmt.Printf("❌ خطا در طول Shutdown: %v\n", err)
-			}
-			shutdownMgr.SignalShutdownComplete()
-			return
-		}
+// command is a CLI subcommand
+type command struct {
+	summary string
+	// setup registers the command's flags on top of the common ones and
+	// returns the body, which runs after the flags are parsed
+	setup func(fs *flag.FlagSet, cfg *config.Config) func(context.Context) int
+	// args is the number of positional arguments the command accepts
+	args int
+	// ownsSignals is set for commands that handle SIGINT and SIGTERM with
+	// a shutdown manager; the others get a context cancelled by them
+	ownsSignals bool
+}
 
-		select {
-		case <-ticker.C:
-			if shutdownMgr.IsRunning() {
-				analyzeGold(cfg)
-			}
+var commands = map[string]command{
+	"analyze":  {"run one analysis per symbol and exit", analyzeCommand, 0, false},
+	"watch":    {"analyze continuously until interrupted (default)", watchCommand, 0, true},
+	"serve":    {"watch and serve the HTTP API", serveCommand, 0, true},
+	"fetch":    {"export candles as CSV or JSON", fetchCommand, 0, false},
+	"backtest": {"replay the strategy on history with paper trades", backtestCommand, 0, false},
+	"report":   {"write an HTML report with SVG charts", reportCommand, 0, false},
+	"config":   {"print the effective configuration ('config print')", configCommand, 1, false},
+}
 
-		case <-shutdownMgr.GetShutdownChan():
-			return
-		}
-	}
+// commandOrder is the order commands are listed in the usage
+var commandOrder = []string{"analyze", "watch", "serve", "fetch", "backtest", "report", "config"}
+
+// flagKeys maps flags to the config keys they set, for config print
+var flagKeys = map[string]string{
+	"symbol":         "symbol",
+	"interval":       "interval",
+	"range":          "range",
+	"lang":           "lang",
+	"log-level":      "log_level",
+	"output":         "output_format",
+	"check-interval": "check_interval",
+	"alerts":         "alerts_file",
+	"state":          "state_file",
+	"addr":           "http_addr",
 }
 
-// analyzeGold performs the gold analysis
-func analyzeGold(cfg *config.Config) {
-	now := time.Now()
-	fmt.Printf("\n📊 بررسی در: %s\n", now.Format("2006-01-02 15:04:05"))
-	fmt.Println(strings.Repeat("-", 70))
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
 
-	// دریافت داده‌ها
-	candles, err := yahoo.FetchCandles(cfg.Symbol, cfg.Interval, cfg.Range)
-	if err != nil {
-		fmt.Printf("❌ خطا در دریافت داده: %v\n", err)
-		logError(cfg, err.Error())
-		return
+// run dispatches to a subcommand and returns the exit code. Without a
+// command the analyzer watches, as it always did.
+func run(args []string) int {
+	name := "watch"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
 	}
-
-	if len(candles) == 0 {
-		fmt.Println("⚠️  داده‌ای برای تجزیه و تحلیل وجود ندارد")
-		return
+	if name == "help" {
+		usage(os.Stdout)
+		return exitOK
 	}
 
-	// استخراج داده‌ها
-	var close, high, low []float64
-	for _, c := range candles {
-		close = append(close, c.Close)
-		high = append(high, c.High)
-		low = append(low, c.Low)
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		usage(os.Stderr)
+		return exitUsage
 	}
 
-	// محاسبه اندیکاتورها
-	rsi := indicators.RSI(close, cfg.RSIPeriod)
-	macd, signal, hist := indicators.MACD(close)
-	atr := indicators.ATR(high, low, close, cfg.ATRPeriod)
-
-	lastIdx := len(close) - 1
-	currentPrice := close[lastIdx]
-	lastRSI := rsi[lastIdx]
-	lastMACD := macd[lastIdx]
-	lastSignalValue := signal[lastIdx]
-	lastHist := hist[lastIdx]
-	lastATR := atr[lastIdx]
-
-	// نمایش قیمت فعلی
-	fmt.Printf("\n💰 قیمت فعلی طلا: %.2f USD\n", currentPrice)
-
-	// نمایش تغییر قیمت (اگر داده کافی باشد)
-	if lastIdx > 0 {
-		prevPrice := close[lastIdx-1]
-		change := currentPrice - prevPrice
-		changePercent := (change / prevPrice) * 100
-		arrow := "↑"
-		if change < 0 {
-			arrow = "↓"
+	cfg, body, err := parse(name, cmd, args, os.Stderr)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
 		}
-		fmt.Printf("   %s تغییر: %.2f USD (%.2f%%)\n", arrow, change, changePercent)
+		return exitUsage
 	}
-
-	// نمایش اندیکاتورها
-	fmt.Println("\n📈 اندیکاتورهای تکنیکال:")
-	fmt.Printf("   • RSI (%d):        %.2f", cfg.RSIPeriod, lastRSI)
-	if lastRSI < cfg.RSIBuyLower {
-		fmt.Print(" 🟦 فروش زیادی")
-	} else if lastRSI > cfg.RSISellThreshold {
-		fmt.Print(" 🟥 خرید زیادی")
-	} else if lastRSI > cfg.RSIBuyLower && lastRSI < cfg.RSIBuyUpper {
-		fmt.Print(" 🟩 محدوده مناسب")
+	// SIGHUP reads the file and environment again with the same flags
+	reloadConfig = func() (*config.Config, error) {
+		cfg, _, err := parse(name, cmd, args, io.Discard)
+		return cfg, err
 	}
-	fmt.Println()
 
-	fmt.Printf("   • MACD:            %.6f\n", lastMACD)
-	fmt.Printf("   • MACD Signal:     %.6f\n", lastSignalValue)
-	fmt.Printf("   • MACD Histogram:  %.6f", lastHist)
-	if lastHist > 0 {
-		fmt.Print(" 📈 مثبت")
-	} else {
-		fmt.Print(" 📉 منفی")
+	setupPrinter(cfg)
+	// config print shows the values it is asked to explain, valid or not
+	if name != "config" {
+		if err := cfg.Validate(); err != nil {
+			printInvalid(os.Stderr, err)
+			return exitUsage
+		}
 	}
-	fmt.Println()
-
-	fmt.Printf("   • ATR (%d):        %.2f\n", cfg.ATRPeriod, lastATR)
-
-	// محاسبه سیگنال
-	strategySignal := strategy.GoldStrategy(rsi, hist, atr, currentPrice)
 
-	// Store last signal
-	lastSignal = strategySignal
-
-	// نمایش سیگنال و توصیه
-	fmt.Println("\n🎯 سیگنال معاملاتی:")
-	switch strategySignal {
-	case strategy.BUY:
-		fmt.Println("   ✅ سیگنال: خریدش کن (BUY)")
-		printBuyReason(cfg, lastRSI, lastHist, lastATR)
-	case strategy.SELL:
-		fmt.Println("   ❌ سیگنال: بفروش (SELL)")
-		printSellReason(cfg, lastRSI, lastHist)
-	case strategy.HOLD:
-		fmt.Println("   ⏸️  سیگنال: نگاه کن (HOLD)")
-		printHoldReason(lastRSI, lastHist, lastATR)
+	if err := setup(cfg); err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("app.log_init_failed", err))
+		return exitUsage
 	}
+	defer logOutput.Close()
 
-	logSignal(cfg, strategySignal, currentPrice, lastRSI, lastHist, lastATR)
-	fmt.Println(strings.Repeat("=", 70))
+	// A second handler would see every Ctrl+C of watch and serve as well
+	if cmd.ownsSignals {
+		return body(context.Background())
+	}
+	// Ctrl+C or SIGTERM cancels the fetch of one-shot commands
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return body(ctx)
 }
 
-func printBuyReason(cfg *config.Config, rsi, hist, atr float64) {
-	fmt.Println("   دلایل سیگنال خرید:")
-	if rsi > cfg.RSIBuyLower && rsi < cfg.RSIBuyUpper {
-		fmt.Printf("      • RSI در محدوده مناسب (%.2f - %.2f)\n", cfg.RSIBuyLower, cfg.RSIBuyUpper)
+// reloadConfig rebuilds the configuration of the running command
+var reloadConfig func() (*config.Config, error)
+
+// parse builds the configuration of a command from the config file, the
+// environment and the flags, reporting problems to w, and returns the
+// command body
+func parse(name string, cmd command, args []string, w io.Writer) (*config.Config, func(context.Context) int, error) {
+	// Flags default to the file and environment so they override both
+	path := configPath(args)
+	cfg, err := config.Load(path)
+	if err != nil {
+		fmt.Fprintln(w, err)
+		return nil, nil, err
 	}
-	if hist > 0 {
-		fmt.Println("      • MACD Histogram مثبت است")
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(w)
+	fs.String("config", path, "JSON, YAML or TOML config file (CONFIG_FILE)")
+	fs.StringVar(&cfg.Symbol, "symbol", cfg.Symbol, "symbol to analyze (SYMBOL)")
+	fs.StringVar(&cfg.Interval, "interval", cfg.Interval, "candle interval, e.g. 5m, 1h or 1d (INTERVAL)")
+	fs.StringVar(&cfg.Range, "range", cfg.Range, "history to fetch, e.g. 5d or 1mo (RANGE)")
+	fs.StringVar(&cfg.Lang, "lang", cfg.Lang, "output language: fa or en (UI_LANG)")
+	fs.StringVar(&cfg.LogLevel, "log-level", cfg.LogLevel, "debug, info, warn or error (LOG_LEVEL)")
+	body := cmd.setup(fs, cfg)
+
+	// Positional arguments may come before the flags ("config print -symbol X")
+	n := 0
+	for n < cmd.args && n < len(args) && !strings.HasPrefix(args[n], "-") {
+		n++
 	}
-	fmt.Printf("      • ATR = %.2f (نوسان خوب)\n", atr)
-}
+	args = append(args[n:len(args):len(args)], args[:n]...)
 
-func printSellReason(cfg *config.Config, rsi, hist float64) {
-	fmt.Println("   دلایل سیگنال فروش:")
-	if rsi > cfg.RSISellThreshold {
-		fmt.Printf("      • RSI بالا است (%.2f > %.2f) - اشباع خریدار\n", rsi, cfg.RSISellThreshold)
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
 	}
-	if hist < 0 {
-		fmt.Println("      • MACD Histogram منفی است - تضعیف روند")
+	if fs.NArg() > cmd.args {
+		err := fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args()[cmd.args:], " "))
+		fmt.Fprintln(w, err)
+		return nil, nil, err
 	}
+	fs.Visit(func(f *flag.Flag) {
+		if key, ok := flagKeys[f.Name]; ok {
+			cfg.SetSource(config.SourceFlag, key)
+		}
+	})
+	return cfg, body, nil
 }
 
-func printHoldReason(rsi, hist, atr float64) {
-	fmt.Println("   دلایل سیگنال انتظار:")
-	fmt.Printf("      • شرایط بازار مناسب برای ورود نیست\n")
-	fmt.Printf("      • RSI = %.2f, MACD Hist = %.6f, ATR = %.2f\n", rsi, hist, atr)
-}
-
-func logSignal(cfg *config.Config, sig strategy.Signal, price, rsi, hist, atr float64) {
-	if cfg.LogFile == "" {
-		return
-	}
-
-	logEntry := fmt.Sprintf("[%s] Signal: %s | Price: %.2f | RSI: %.2f | MACD: %.6f | ATR: %.2f\n",
-		time.Now().Format("2006-01-02 15:04:05"), sig, price, rsi, hist, atr)
-
-	f, err := os.OpenFile(cfg.LogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return
+// configPath finds the config file before the flags are parsed, since
+// the file provides their defaults
+func configPath(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "config" {
+			continue
+		}
+		if hasValue {
+			return value
+		}
+		if i+1 < len(args) {
+			return args[i+1]
+		}
 	}
-	defer f.Close()
-
-	f.WriteString(logEntry)
+	return os.Getenv("CONFIG_FILE")
 }
 
-func logError(cfg *config.Config, errMsg string) {
-	if cfg.LogFile == "" {
-		return
+// setupPrinter configures the language and calendar of the output
+func setupPrinter(cfg *config.Config) {
+	printer := i18n.New(i18n.Resolve(cfg.Lang))
+	printer.Isolate = printer.RTL() && cfg.BidiIsolate
+	// Invalid calendar settings are reported by Validate
+	if calendar, err := i18n.ParseCalendar(cfg.TimeCalendar); err == nil {
+		printer.Calendar = calendar
+		printer.Location, _ = i18n.LoadLocation(cfg.TimeZone, calendar)
 	}
+	i18n.SetDefault(printer)
+}
 
-	logEntry := fmt.Sprintf("[%s] ERROR: %s\n", time.Now().Format("2006-01-02 15:04:05"), errMsg)
-
-	f, err := os.OpenFile(cfg.LogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+// setup configures the logger
+func setup(cfg *config.Config) error {
+	logger, out, err := logging.New(cfg)
 	if err != nil {
-		return
+		return err
 	}
-	defer f.Close()
-
-	f.WriteString(logEntry)
+	logOutput = out
+	slog.SetDefault(logger)
+	return nil
 }
 
-// saveShutdownStats saves statistics before shutdown
-func saveShutdownStats(cfg *config.Config) error {
-	if cfg.LogFile == "" {
-		return nil
+// printInvalid lists every configuration problem, one per line
+func printInvalid(w io.Writer, err error) {
+	fmt.Fprintln(w, i18n.T("config.invalid"))
+	for _, line := range strings.Split(err.Error(), "\n") {
+		fmt.Fprintln(w, i18n.T("config.problem", line))
 	}
+}
 
-	logEntry := fmt.Sprintf("[%s] SHUTDOWN: برنامه با موفقیت متوقف شد - آخرین سیگنال: %s\n",
-		time.Now().Format("2006-01-02 15:04:05"), lastSignal)
-
-	f, err := os.OpenFile(cfg.LogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+// setupOutput selects the console, machine-readable or dashboard output.
+// The dashboard only makes sense for a continuous run.
+func setupOutput(cfg *config.Config, continuous bool) error {
+	format, err := output.ParseFormat(cfg.OutputFormat)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
 
-	_, err = f.WriteString(logEntry)
-	if err == nil {
-		fmt.Printf("   ✓ لاگ‌های نهایی ذخیره شدند (%s)\n", cfg.LogFile)
+	switch format {
+	case output.Console:
+	case output.TUI:
+		if !continuous {
+			return fmt.Errorf("%s output needs watch or serve", format)
+		}
+		// The dashboard owns the screen; details remain in the log
+		dashboard = tui.New(cfg)
+		console = io.Discard
+	default:
+		renderer, _ = output.New(format, os.Stdout)
+		console = os.Stderr
 	}
-	return err
+	return nil
 }
 
-// closeResources closes any open resources
-func closeResources(cfg *config.Config) error {
-	fmt.Println("\n🔐 بستن منابع...")
-	fmt.Println("   ✓ تمام منابع بسته شدند")
-	return nil
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: analyzer [command] [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, name := range commandOrder {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Flags override the environment, which overrides the config file.")
+	fmt.Fprintln(w, "Run 'analyzer <command> -h' for the flags of a command.")
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Exit codes: %d success, %d failure, %d usage or configuration error,\n", exitOK, exitFailure, exitUsage)
+	fmt.Fprintf(w, "%d shutdown forced by a second Ctrl+C or SIGTERM.\n", exitForced)
 }
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"gold-analyzer/config"
+	"gold-analyzer/i18n"
+)
+
+// restartKeys are settings read once at startup; a reload reports but
+// does not apply them
+var restartKeys = map[string]bool{
+	"enable_notifications": true,
+	"notify_queue_file":    true,
+	"state_file":           true,
+	"output_format":        true,
+	"log_file":             true,
+	"log_level":            true,
+	"log_format":           true,
+	"log_max_size_mb":      true,
+	"log_rotate_interval":  true,
+	"log_max_backups":      true,
+	"log_compress":         true,
+	"shutdown_timeout":     true,
+	"http_addr":            true,
+}
+
+// reload reads the config file and environment again and applies the
+// language, dashboard and notifier settings, and reads the alert rules
+// again even when nothing else changed. The caller swaps in the returned
+// configuration for thresholds and the watchlist. On any problem the
+// current configuration stays in effect and ok is false.
+func reload(cur *config.Config) (next *config.Config, ok bool) {
+	next, err := reloadConfig()
+	if err == nil {
+		err = next.Validate()
+	}
+	if err != nil {
+		fmt.Fprintln(console, i18n.T("reload.failed"))
+		printInvalid(console, err)
+		slog.Error("config reload failed", "error", err)
+		return cur, false
+	}
+
+	changes := config.Diff(cur, next)
+	if len(changes) == 0 {
+		fmt.Fprintln(console, i18n.T("reload.unchanged"))
+		slog.Info("config reloaded", "changes", 0)
+		// The rules file may have changed even if its path did not
+		loadAlerts(cur, cur.Targets()[0])
+		return cur, false
+	}
+
+	fmt.Fprintln(console, i18n.T("reload.applied", len(changes)))
+	for _, c := range changes {
+		old, new := displayValue(c.Key, c.Old), displayValue(c.Key, c.New)
+		if restartKeys[c.Key] {
+			fmt.Fprintln(console, i18n.T("reload.restart", c.Key, old, new))
+			slog.Warn("config change needs a restart", "key", c.Key, "old", old, "new", new)
+			continue
+		}
+		fmt.Fprintln(console, i18n.T("reload.change", c.Key, old, new))
+		slog.Info("config changed", "key", c.Key, "old", old, "new", new, "source", next.Source(c.Key))
+	}
+	slog.Info("config reloaded", "changes", len(changes))
+
+	setupPrinter(next)
+	if dashboard != nil {
+		// Drawn with the first symbol until its next analysis
+		dashboard.SetConfig(next.Targets()[0])
+	}
+	if dispatcher != nil {
+		configureWebhook(dispatcher, next)
+	}
+	loadAlerts(next, next.Targets()[0])
+	return next, true
+}
+
+// displayValue masks secrets in printed settings
+func displayValue(key, value string) string {
+	if key == "notify_webhook_url" && value != "" {
+		// The URL usually embeds a token
+		return "****"
+	}
+	return value
+}
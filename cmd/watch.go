@@ -0,0 +1,754 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"gold-analyzer/alerts"
+	"gold-analyzer/analysis"
+	"gold-analyzer/api"
+	"gold-analyzer/backtest"
+	"gold-analyzer/config"
+	"gold-analyzer/health"
+	"gold-analyzer/i18n"
+	"gold-analyzer/logging"
+	"gold-analyzer/metrics"
+	"gold-analyzer/model"
+	"gold-analyzer/notify"
+	"gold-analyzer/output"
+	"gold-analyzer/shutdown"
+	"gold-analyzer/state"
+	"gold-analyzer/strategy"
+	"gold-analyzer/tui"
+	"gold-analyzer/yahoo"
+)
+
+// lastSignals is the last signal of every watched symbol
+var lastSignals = make(map[string]strategy.Signal)
+
+// positions is the open paper position of every watched symbol, traded
+// like a backtest: a BUY opens one at the close, a SELL closes it
+var positions = make(map[string]*backtest.Trade)
+
+// restored is the symbol state saved by the previous run, kept for
+// symbols not analyzed yet when the state is saved again
+var restored map[string]state.Symbol
+
+// dispatcher delivers signal notifications (nil when notifications are disabled)
+var dispatcher *notify.Dispatcher
+
+// alertEngine evaluates user defined alerts (nil when no alerts file is set)
+var alertEngine *alerts.Engine
+
+// results keeps the latest analysis and signal history served by the API
+var results = analysis.NewStore(100)
+
+// console receives the decorated human-readable output; it is stderr when
+// stdout carries machine-readable results
+var console io.Writer = os.Stdout
+
+// renderer writes each analysis result for OUTPUT_FORMAT json, ndjson or csv
+// (nil for the decorated console output)
+var renderer output.Renderer
+
+// dashboard replaces the console output for OUTPUT_FORMAT=tui (nil otherwise)
+var dashboard *tui.Dashboard
+
+// frame is the last dashboard state, kept so failed fetches still redraw it
+var frame tui.State
+
+// dashboardSymbol is the watchlist symbol shown on the dashboard
+var dashboardSymbol string
+
+// fetched holds the candles of the current cycle so alerts reuse them
+var fetched = make(map[alerts.Feed][]model.Candle)
+
+// logOutput is the shared log destination, synced on shutdown
+var logOutput logging.Output
+
+// fetchHealth tracks fetch outcomes for the health endpoints
+var fetchHealth = health.NewTracker()
+
+// watch runs the monitoring loop until a shutdown signal; serve also
+// starts the HTTP API.
+func watch(cfg *config.Config, serve bool) int {
+	targets := cfg.Targets()
+	symbols := make([]string, len(targets))
+	for i, t := range targets {
+		symbols[i] = t.Symbol
+	}
+	dashboardSymbol = targets[0].Symbol
+
+	fmt.Fprintln(console, i18n.T("app.title"))
+	fmt.Fprintln(console, strings.Repeat("=", 70))
+	fmt.Fprintln(console, i18n.T("app.settings"))
+	fmt.Fprintln(console, i18n.T("app.symbol", strings.Join(symbols, ", ")))
+	fmt.Fprintln(console, i18n.T("app.interval", cfg.Interval))
+	fmt.Fprintln(console, i18n.T("app.range", cfg.Range))
+	fmt.Fprintln(console, i18n.T("app.check_interval", cfg.CheckInterval))
+	fmt.Fprintln(console, strings.Repeat("=", 70))
+	fmt.Fprintln(console, i18n.T("app.stop_hint"))
+
+	// Create shutdown manager
+	shutdownMgr := shutdown.NewManager()
+	out := console
+	if dashboard != nil {
+		// Shutdown progress is printed after the dashboard is closed
+		out = os.Stdout
+	}
+	shutdownMgr.Observer = shutdown.Observers{
+		&shutdown.ConsoleObserver{Out: out},
+		&shutdown.LogObserver{Logger: slog.Default()},
+	}
+
+	if cfg.EnableNotifications {
+		d, err := setupNotifications(cfg)
+		if err != nil {
+			fmt.Fprintln(console, i18n.T("notify.queue_load_failed", err))
+			slog.Error("failed to load notification queue", "file", cfg.NotifyQueueFile, "error", err)
+		}
+		dispatcher = d
+
+		// Deliver or persist pending notifications before exit
+		shutdownMgr.Register(shutdown.Hook{
+			Name:  "notifications",
+			Phase: shutdown.PhaseFlush,
+			Fn:    dispatcher.Flush,
+		})
+	}
+
+	loadAlerts(cfg, targets[0])
+	restoreState(cfg)
+
+	// API failures stop the loop and fail the command
+	var apiFailed atomic.Bool
+	var server *api.Server
+	if serve {
+		server = api.NewServer(cfg.HTTPAddr, cfg, results)
+		server.SetHealth(fetchHealth, shutdownMgr)
+		errCh := server.Start()
+		fmt.Fprintln(console, i18n.T("api.listening", cfg.HTTPAddr))
+
+		go func() {
+			if err := <-errCh; err != nil {
+				fmt.Fprintln(console, i18n.T("api.failed", err))
+				slog.Error("api server failed", "addr", cfg.HTTPAddr, "error", err)
+				apiFailed.Store(true)
+				shutdownMgr.Stop()
+			}
+		}()
+
+		// /readyz reports shutting_down for a while before the listener
+		// closes, so load balancers stop routing to this instance first
+		shutdownMgr.Register(shutdown.Hook{
+			Name:  "api drain",
+			Phase: shutdown.PhaseStopIntake,
+			Fn:    func(ctx context.Context) error { return server.Drain(ctx, cfg.HTTPDrain) },
+		})
+		shutdownMgr.Register(shutdown.Hook{
+			Name:     "api server",
+			Phase:    shutdown.PhaseStopIntake,
+			Priority: 1,
+			Fn:       server.Shutdown,
+		})
+	}
+
+	// Register shutdown hooks
+	if cfg.StateFile != "" {
+		shutdownMgr.Register(shutdown.Hook{
+			Name:  "state",
+			Phase: shutdown.PhaseClose,
+			Fn:    func(context.Context) error { return saveState(cfg) },
+		})
+	}
+
+	shutdownMgr.Register(shutdown.Hook{
+		Name:  "resources",
+		Phase: shutdown.PhaseClose,
+		Fn:    func(context.Context) error { return closeResources(cfg) },
+	})
+
+	shutdownMgr.Register(shutdown.Hook{
+		Name:  "stats",
+		Phase: shutdown.PhaseFinal,
+		Fn:    func(context.Context) error { return saveShutdownStats(cfg) },
+	})
+
+	// Flush pending log writes and rotated file compression, after every
+	// other hook has logged
+	shutdownMgr.Register(shutdown.Hook{
+		Name:     "logs",
+		Phase:    shutdown.PhaseFinal,
+		Priority: 1,
+		Fn:       func(context.Context) error { return logOutput.Sync() },
+	})
+
+	// Start signal handling
+	shutdownMgr.Start()
+
+	// The context is cancelled on the signal, interrupting in-flight
+	// fetches, analysis and notifications
+	ctx := shutdownMgr.Context()
+	go func() {
+		sig := shutdownMgr.WaitForShutdown()
+		fmt.Fprintln(console, i18n.T("signal.received", sig))
+		fmt.Fprintln(console, i18n.T("signal.stopping"))
+	}()
+
+	// SIGHUP reloads the configuration instead of stopping
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	// The ticker runs at the shortest symbol interval; each symbol is
+	// analyzed when its own interval has passed
+	step := tickStep(targets)
+	ticker := time.NewTicker(step)
+	defer ticker.Stop()
+
+	// اجرای اولی بدون تاخیر
+	lastRun := make(map[string]time.Time)
+	runCycle(ctx, targets, lastRun, time.Now(), step)
+
+	// حلقه نظارت
+	for {
+		select {
+		case <-ctx.Done():
+			if dashboard != nil {
+				dashboard.Close()
+			}
+
+			// Perform graceful shutdown
+			code := exitOK
+			if err := shutdownMgr.Shutdown(cfg.ShutdownTimeout); err != nil {
+				fmt.Fprintln(console, i18n.T("shutdown.error", err))
+				code = exitFailure
+			}
+			shutdownMgr.SignalShutdownComplete()
+			if apiFailed.Load() {
+				code = exitFailure
+			}
+			return code
+
+		case now := <-ticker.C:
+			runCycle(ctx, targets, lastRun, now, step)
+
+		case <-hup:
+			// Swapped between cycles, so a run never mixes settings
+			next, ok := reload(cfg)
+			if !ok {
+				continue
+			}
+			cfg, targets = next, next.Targets()
+			if dashboardSymbol != targets[0].Symbol {
+				// The frame belongs to the symbol no longer shown
+				frame = tui.State{}
+			}
+			dashboardSymbol = targets[0].Symbol
+			symbols = symbols[:0]
+			for _, t := range targets {
+				symbols = append(symbols, t.Symbol)
+			}
+			fetchHealth.Retain(symbols...)
+			if server != nil {
+				server.SetConfig(cfg)
+			}
+			step = tickStep(targets)
+			ticker.Reset(step)
+		}
+	}
+}
+
+// tickStep is the shortest check interval of the targets
+func tickStep(targets []*config.Config) time.Duration {
+	step := targets[0].CheckInterval
+	for _, t := range targets {
+		step = min(step, t.CheckInterval)
+	}
+	return step
+}
+
+// runCycle analyzes the symbols that are due and then evaluates alerts.
+// A symbol is due when its interval has passed, give or take half a tick;
+// symbols added by a reload are due at once.
+func runCycle(ctx context.Context, targets []*config.Config, lastRun map[string]time.Time, now time.Time, step time.Duration) {
+	clear(fetched)
+	ran := false
+	for _, t := range targets {
+		if ctx.Err() != nil {
+			return
+		}
+		if last, ok := lastRun[t.Symbol]; ok && now.Sub(last)+step/2 < t.CheckInterval {
+			continue
+		}
+		lastRun[t.Symbol] = now
+		analyzeGold(ctx, t)
+		ran = true
+	}
+
+	if ran && alertEngine != nil {
+		checkAlerts(ctx)
+	}
+}
+
+// analyzeGold performs the gold analysis. The error is reported on the
+// console and in the log already; it only decides the exit code of analyze.
+func analyzeGold(ctx context.Context, cfg *config.Config) error {
+	now := time.Now()
+	defer func() {
+		metrics.AnalysisDuration.Observe(metrics.Since(now), cfg.Symbol)
+	}()
+	onDashboard := dashboard != nil && cfg.Symbol == dashboardSymbol
+	if onDashboard {
+		defer drawDashboard(cfg)
+	}
+
+	if renderer == nil {
+		fmt.Fprintln(console, i18n.T("analysis.checked_at", i18n.Time(now)))
+		fmt.Fprintln(console, i18n.T("analysis.symbol", cfg.Symbol, cfg.Interval))
+		fmt.Fprintln(console, strings.Repeat("-", 70))
+	}
+
+	// ارسال مجدد اعلان‌های معوق
+	if dispatcher != nil {
+		if err := dispatcher.RetryPending(ctx); err != nil {
+			slog.Warn("notification retry failed", "error", err)
+		}
+	}
+
+	// دریافت داده‌ها
+	candles, err := yahoo.FetchCandles(ctx, cfg.Symbol, cfg.Interval, cfg.Range)
+	if ctx.Err() != nil {
+		// Stopping, not a fetch failure
+		slog.Info("analysis interrupted by shutdown", "symbol", cfg.Symbol)
+		return ctx.Err()
+	}
+	fetchHealth.RecordFetch(cfg.Symbol, err)
+	if err != nil {
+		fmt.Fprintln(console, i18n.T("analysis.fetch_failed", err))
+		slog.Error("fetch failed", "symbol", cfg.Symbol, "interval", cfg.Interval, "error", err)
+		return err
+	}
+
+	if len(candles) == 0 {
+		fmt.Fprintln(console, i18n.T("analysis.no_data"))
+		return errors.New("no data to analyze")
+	}
+
+	res, err := analysis.Analyze(ctx, cfg, candles)
+	if err != nil {
+		fmt.Fprintln(console, i18n.T("analysis.failed", err))
+		slog.Error("analysis failed", "symbol", cfg.Symbol, "interval", cfg.Interval, "error", err)
+		return err
+	}
+	fetched[alerts.Feed{Symbol: cfg.Symbol, Interval: cfg.Interval}] = candles
+	results.Record(res)
+	metrics.ObserveResult(res)
+	if onDashboard {
+		frame.Result = res
+		frame.Candles = candles
+		frame.Reasons = signalReasons(cfg, res)
+	}
+
+	if renderer != nil {
+		if err := renderer.Render(res); err != nil {
+			slog.Error("output failed", "format", cfg.OutputFormat, "error", err)
+		}
+	} else {
+		printReport(cfg, res, len(candles) > 1)
+	}
+
+	// Notify only on signal transitions, HOLD is not actionable
+	if dispatcher != nil && res.Signal != lastSignals[cfg.Symbol] && res.Signal != strategy.HOLD {
+		notifySignal(ctx, cfg, res.Signal, res.Price, res.Indicators.RSI, res.Indicators.MACDHist, res.Indicators.ATR)
+	}
+
+	// Store last signal
+	lastSignals[cfg.Symbol] = res.Signal
+	tradePaper(res)
+
+	logSignal(res)
+	if renderer == nil {
+		fmt.Fprintln(console, strings.Repeat("=", 70))
+	}
+	return nil
+}
+
+// tradePaper opens or closes the paper position of the symbol on a BUY or
+// SELL signal
+func tradePaper(res analysis.Result) {
+	pos := positions[res.Symbol]
+	switch {
+	case res.Signal == strategy.BUY && pos == nil:
+		positions[res.Symbol] = &backtest.Trade{EntryTime: res.CandleTime, EntryPrice: res.Price, Open: true}
+		if renderer == nil {
+			fmt.Fprintln(console, i18n.T("paper.opened", res.Price))
+		}
+		slog.Info("paper position opened", "symbol", res.Symbol, "price", res.Price)
+	case res.Signal == strategy.SELL && pos != nil:
+		pos.Close(res.CandleTime, res.Price)
+		delete(positions, res.Symbol)
+		if renderer == nil {
+			fmt.Fprintln(console, i18n.T("paper.closed", res.Price, pos.Return*100))
+		}
+		slog.Info("paper position closed", "symbol", res.Symbol,
+			"entry_price", pos.EntryPrice, "price", res.Price, "return", pos.Return)
+	}
+}
+
+// drawDashboard redraws the dashboard with the latest state of the target
+// cfg, drawn with its own periods and thresholds
+func drawDashboard(cfg *config.Config) {
+	dashboard.SetConfig(cfg)
+	frame.History = results.History(cfg.Symbol)
+	frame.Fetch = fetchHealth.Symbol(cfg.Symbol)
+	if err := dashboard.Draw(frame); err != nil {
+		slog.Error("dashboard draw failed", "error", err)
+	}
+}
+
+// printReport prints the decorated console report of one analysis run
+func printReport(cfg *config.Config, res analysis.Result, hasChange bool) {
+	currentPrice := res.Price
+	lastRSI := res.Indicators.RSI
+	lastMACD := res.Indicators.MACD
+	lastSignalValue := res.Indicators.MACDSignal
+	lastHist := res.Indicators.MACDHist
+	lastATR := res.Indicators.ATR
+
+	// نمایش قیمت فعلی
+	fmt.Fprintln(console, i18n.T("analysis.price", currentPrice))
+
+	// نمایش تغییر قیمت (اگر داده کافی باشد)
+	if hasChange {
+		arrow := "↑"
+		if res.Change < 0 {
+			arrow = "↓"
+		}
+		fmt.Fprintln(console, i18n.T("analysis.change", arrow, res.Change, res.ChangePercent))
+	}
+
+	// نمایش اندیکاتورها
+	fmt.Fprintln(console, i18n.T("analysis.indicators"))
+	fmt.Fprint(console, i18n.T("analysis.rsi", cfg.RSIPeriod, lastRSI))
+	if lastRSI < cfg.RSIBuyLower {
+		fmt.Fprint(console, i18n.T("analysis.rsi_oversold"))
+	} else if lastRSI > cfg.RSISellThreshold {
+		fmt.Fprint(console, i18n.T("analysis.rsi_overbought"))
+	} else if lastRSI > cfg.RSIBuyLower && lastRSI < cfg.RSIBuyUpper {
+		fmt.Fprint(console, i18n.T("analysis.rsi_buy_zone"))
+	}
+	fmt.Fprintln(console)
+
+	fmt.Fprintln(console, i18n.T("analysis.macd", lastMACD))
+	fmt.Fprintln(console, i18n.T("analysis.macd_signal", lastSignalValue))
+	fmt.Fprint(console, i18n.T("analysis.macd_hist", lastHist))
+	if lastHist > 0 {
+		fmt.Fprint(console, i18n.T("analysis.hist_positive"))
+	} else {
+		fmt.Fprint(console, i18n.T("analysis.hist_negative"))
+	}
+	fmt.Fprintln(console)
+
+	fmt.Fprintln(console, i18n.T("analysis.atr", cfg.ATRPeriod, lastATR))
+
+	// نمایش سیگنال و توصیه
+	fmt.Fprintln(console, i18n.T("analysis.signal_header"))
+	switch res.Signal {
+	case strategy.BUY:
+		fmt.Fprintln(console, i18n.T("signal.buy"))
+	case strategy.SELL:
+		fmt.Fprintln(console, i18n.T("signal.sell"))
+	case strategy.HOLD:
+		fmt.Fprintln(console, i18n.T("signal.hold"))
+	}
+	for _, line := range signalReasons(cfg, res) {
+		fmt.Fprintln(console, line)
+	}
+}
+
+// checkAlerts evaluates user defined alerts, reusing the candles fetched
+// in this cycle when they are enough for the rules and fetching the range
+// the rules need otherwise
+func checkAlerts(ctx context.Context) {
+	var triggers []alerts.Trigger
+	for _, feed := range alertEngine.Feeds() {
+		feedCandles, ok := fetched[feed]
+		if !ok || len(feedCandles) < alertEngine.Bars(feed) {
+			var err error
+			feedCandles, err = yahoo.FetchCandles(ctx, feed.Symbol, feed.Interval, alertEngine.Range(feed))
+			if ctx.Err() != nil {
+				// Stopping, not a fetch failure
+				slog.Info("alert check interrupted by shutdown", "symbol", feed.Symbol)
+				return
+			}
+			if err != nil {
+				fmt.Fprintln(console, i18n.T("alerts.fetch_failed", feed.Symbol, feed.Interval, err))
+				slog.Error("fetch failed", "symbol", feed.Symbol, "interval", feed.Interval, "error", err)
+				continue
+			}
+		}
+
+		fired, err := alertEngine.Evaluate(feed, feedCandles)
+		if err != nil {
+			fmt.Fprintln(console, i18n.T("alerts.eval_failed", err))
+			slog.Warn("alert evaluation failed", "symbol", feed.Symbol, "interval", feed.Interval, "error", err)
+		}
+		triggers = append(triggers, fired...)
+	}
+
+	if len(triggers) == 0 {
+		return
+	}
+
+	// With notifications on, the console channel prints the alerts
+	if dispatcher == nil {
+		fmt.Fprintln(console, i18n.T("alerts.header"))
+	}
+	for _, t := range triggers {
+		logAlert(t)
+		if dispatcher == nil {
+			fmt.Fprintln(console, i18n.T("alerts.item", t.Message()))
+			continue
+		}
+
+		event := notify.NewEvent("alert", t.Rule.Symbol, t.Message(), t.Price)
+		if err := dispatcher.Dispatch(ctx, event); err != nil {
+			fmt.Fprintln(console, i18n.T("notify.failed", err))
+			slog.Warn("notification failed", "kind", event.Kind, "symbol", event.Symbol, "error", err)
+		}
+	}
+}
+
+// setupNotifications builds the dispatcher with every configured channel
+func setupNotifications(cfg *config.Config) (*notify.Dispatcher, error) {
+	queue, err := notify.NewQueue(cfg.NotifyQueueFile)
+
+	d := notify.NewDispatcher(queue)
+	d.Register(&notify.ConsoleNotifier{Out: console}, nil)
+	configureWebhook(d, cfg)
+
+	return d, err
+}
+
+// configureWebhook registers, replaces or removes the webhook channel
+func configureWebhook(d *notify.Dispatcher, cfg *config.Config) {
+	if cfg.NotifyWebhookURL == "" {
+		d.Unregister("webhook")
+		return
+	}
+	d.Register(notify.NewWebhookNotifier(cfg.NotifyWebhookURL),
+		notify.NewRateLimiter(cfg.NotifyRatePerMinute, 1))
+}
+
+// loadAlerts loads the alert rules, or clears them when no file is set.
+// Rules without a symbol apply to the primary target. Unchanged rules keep
+// their state across a reload, and rules that fail to load leave the
+// previous ones in effect.
+func loadAlerts(cfg, primary *config.Config) {
+	prev := alertEngine
+	if cfg.AlertsFile == "" {
+		alertEngine = nil
+		return
+	}
+
+	rules, err := alerts.LoadRules(cfg.AlertsFile)
+	if err != nil {
+		fmt.Fprintln(console, i18n.T("alerts.load_failed", err))
+		slog.Error("failed to load alerts", "file", cfg.AlertsFile, "error", err)
+		if prev != nil {
+			fmt.Fprintln(console, i18n.T("alerts.kept", len(prev.Rules())))
+		}
+		return
+	}
+	alertEngine = alerts.NewEngine(rules, primary.Symbol, primary.Interval)
+	if prev != nil {
+		alertEngine.Restore(prev.State())
+	}
+	fmt.Fprintln(console, i18n.T("alerts.loaded", len(rules), cfg.AlertsFile))
+}
+
+// notifySignal sends a signal event to all notification channels
+func notifySignal(ctx context.Context, cfg *config.Config, sig strategy.Signal, price, rsi, hist, atr float64) {
+	msg := fmt.Sprintf("RSI: %.2f | MACD Hist: %.6f | ATR: %.2f", rsi, hist, atr)
+	event := notify.NewEvent("signal", cfg.Symbol, msg, price)
+	event.Signal = string(sig)
+
+	if err := dispatcher.Dispatch(ctx, event); err != nil {
+		fmt.Fprintln(console, i18n.T("notify.failed", err))
+		slog.Warn("notification failed", "kind", event.Kind, "symbol", event.Symbol, "error", err)
+	}
+}
+
+// signalReasons explains the signal, starting with a header line
+func signalReasons(cfg *config.Config, res analysis.Result) []string {
+	rsi := res.Indicators.RSI
+	hist := res.Indicators.MACDHist
+	atr := res.Indicators.ATR
+
+	var lines []string
+	switch res.Signal {
+	case strategy.BUY:
+		lines = append(lines, i18n.T("reason.buy_header"))
+		if rsi > cfg.RSIBuyLower && rsi < cfg.RSIBuyUpper {
+			lines = append(lines, i18n.T("reason.buy_rsi", cfg.RSIBuyLower, cfg.RSIBuyUpper))
+		}
+		if hist > 0 {
+			lines = append(lines, i18n.T("reason.buy_macd"))
+		}
+		lines = append(lines, i18n.T("reason.buy_atr", atr))
+	case strategy.SELL:
+		lines = append(lines, i18n.T("reason.sell_header"))
+		if rsi > cfg.RSISellThreshold {
+			lines = append(lines, i18n.T("reason.sell_rsi", rsi, cfg.RSISellThreshold))
+		}
+		if hist < 0 {
+			lines = append(lines, i18n.T("reason.sell_macd"))
+		}
+	case strategy.HOLD:
+		lines = append(lines,
+			i18n.T("reason.hold_header"),
+			i18n.T("reason.hold_market"),
+			i18n.T("reason.hold_values", rsi, hist, atr))
+	}
+	return lines
+}
+
+// logSignal writes the analysis result as a structured log record
+func logSignal(res analysis.Result) {
+	slog.Info("signal",
+		"symbol", res.Symbol,
+		"interval", res.Interval,
+		"signal", string(res.Signal),
+		"price", res.Price,
+		"change_percent", res.ChangePercent,
+		"candle_time", i18n.Time(res.CandleTime),
+		slog.Group("indicators",
+			"rsi", res.Indicators.RSI,
+			"macd", res.Indicators.MACD,
+			"macd_signal", res.Indicators.MACDSignal,
+			"macd_hist", res.Indicators.MACDHist,
+			"atr", res.Indicators.ATR,
+		),
+	)
+}
+
+// logAlert writes a fired alert as a structured log record
+func logAlert(t alerts.Trigger) {
+	slog.Info("alert",
+		"symbol", t.Rule.Symbol,
+		"interval", t.Rule.Interval,
+		"rule", t.Rule.Name,
+		"type", t.Rule.Type,
+		"condition", t.Rule.Condition,
+		"threshold", t.Rule.Value,
+		"value", t.Value,
+		"price", t.Price,
+	)
+}
+
+// saveShutdownStats saves statistics before shutdown
+func saveShutdownStats(cfg *config.Config) error {
+	for symbol, sig := range lastSignals {
+		slog.Info("shutdown", "symbol", symbol, "last_signal", string(sig))
+	}
+	if cfg.LogFile != "" {
+		fmt.Fprintln(console, i18n.T("stats.logs_saved", cfg.LogFile))
+	}
+	return nil
+}
+
+// restoreState restores the last signals, paper positions, alert states
+// and undelivered notifications saved by the previous run, so the first
+// cycle does not repeat their notifications
+func restoreState(cfg *config.Config) {
+	if cfg.StateFile == "" {
+		return
+	}
+
+	snap, err := state.Load(cfg.StateFile)
+	if err != nil {
+		fmt.Fprintln(console, i18n.T("state.load_failed", err))
+		slog.Error("failed to load state", "file", cfg.StateFile, "error", err)
+		return
+	}
+	if snap.SavedAt.IsZero() {
+		// First run
+		return
+	}
+
+	restored = snap.Symbols
+	for symbol, s := range snap.Symbols {
+		lastSignals[symbol] = s.Signal
+		if s.Position != nil {
+			positions[symbol] = s.Position
+		}
+	}
+	rules := 0
+	if alertEngine != nil {
+		rules = alertEngine.Restore(snap.Alerts)
+	}
+	fmt.Fprintln(console, i18n.T("state.restored", len(snap.Symbols), rules, i18n.Time(snap.SavedAt)))
+	slog.Info("state restored", "file", cfg.StateFile, "saved_at", snap.SavedAt,
+		"symbols", len(snap.Symbols), "positions", len(positions), "alerts", rules)
+
+	if len(snap.Pending) == 0 {
+		return
+	}
+	if dispatcher == nil {
+		slog.Warn("undelivered notifications dropped, notifications are disabled", "count", len(snap.Pending))
+		return
+	}
+	for _, d := range snap.Pending {
+		if err := dispatcher.Queue().Push(d); err != nil {
+			slog.Error("failed to save notification queue", "file", cfg.NotifyQueueFile, "error", err)
+		}
+	}
+	fmt.Fprintln(console, i18n.T("state.pending", len(snap.Pending)))
+	slog.Info("undelivered notifications restored", "count", len(snap.Pending))
+}
+
+// saveState writes the last signals, paper positions, alert states and,
+// without a queue file of their own, undelivered notifications for the
+// next run
+func saveState(cfg *config.Config) error {
+	snap := state.Snapshot{Symbols: make(map[string]state.Symbol, len(lastSignals))}
+	for symbol, sig := range lastSignals {
+		s := restored[symbol]
+		s.Signal = sig
+		if res, ok := results.Latest(symbol); ok {
+			s.CandleTime, s.Price = res.CandleTime, res.Price
+		}
+		s.Position = positions[symbol]
+		snap.Symbols[symbol] = s
+	}
+	if alertEngine != nil {
+		snap.Alerts = alertEngine.State()
+	}
+	if dispatcher != nil && cfg.NotifyQueueFile == "" {
+		// Left after the flush hook gave up on them
+		snap.Pending = dispatcher.Queue().Pending()
+	}
+
+	if err := state.Save(cfg.StateFile, snap); err != nil {
+		return err
+	}
+	fmt.Fprintln(console, i18n.T("state.saved", cfg.StateFile))
+	return nil
+}
+
+// closeResources closes any open resources
+func closeResources(cfg *config.Config) error {
+	fmt.Fprintln(console, i18n.T("resources.closing"))
+	fmt.Fprintln(console, i18n.T("resources.closed"))
+	return nil
+}
@@ -1,72 +1,146 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type Config struct {
 	// Symbol to analyze
-	Symbol string
+	Symbol string `json:"symbol"`
 	// Interval for fetching data (1m, 5m, 1h, 1d, etc.)
-	Interval string
+	Interval string `json:"interval"`
 	// Range for fetching data (1d, 5d, 1mo, 3mo, 6mo, 1y, 2y, 5y, 10y, ytd, max)
-	Range string
+	Range string `json:"range"`
 	// Check interval in minutes
-	CheckInterval time.Duration
+	CheckInterval time.Duration `json:"-"`
 	// RSI Period
-	RSIPeriod int
+	RSIPeriod int `json:"rsi_period"`
 	// MACD Fast Period
-	MACDFastPeriod int
+	MACDFastPeriod int `json:"macd_fast_period"`
 	// MACD Slow Period
-	MACDSlowPeriod int
+	MACDSlowPeriod int `json:"macd_slow_period"`
 	// MACD Signal Period
-	MACDSignalPeriod int
+	MACDSignalPeriod int `json:"macd_signal_period"`
 	// ATR Period
-	ATRPeriod int
+	ATRPeriod int `json:"atr_period"`
 	// RSI Buy threshold (lower bound)
-	RSIBuyLower float64
+	RSIBuyLower float64 `json:"rsi_buy_lower"`
 	// RSI Buy threshold (upper bound)
-	RSIBuyUpper float64
+	RSIBuyUpper float64 `json:"rsi_buy_upper"`
 	// RSI Sell threshold
-	RSISellThreshold float64
+	RSISellThreshold float64 `json:"rsi_sell_threshold"`
 	// Enable notifications
-	EnableNotifications bool
-	// Log file path (empty to disable)
-	LogFile string
+	EnableNotifications bool `json:"enable_notifications"`
+	// Webhook URL for notifications (empty to disable the webhook channel)
+	NotifyWebhookURL string `json:"notify_webhook_url"`
+	// File used to persist undelivered notifications (empty = memory only)
+	NotifyQueueFile string `json:"notify_queue_file"`
+	// Maximum notifications per minute for each channel (0 = unlimited)
+	NotifyRatePerMinute int `json:"notify_rate_per_minute"`
+	// Alert rules file path (empty to disable)
+	AlertsFile string `json:"alerts_file"`
+	// File the watch loop saves its state to on shutdown and restores it
+	// from on startup (empty to disable)
+	StateFile string `json:"state_file"`
+	// UI language: fa or en (empty = from LANG, defaulting to fa)
+	Lang string `json:"lang"`
+	// Wrap numbers and English terms in bidi isolates in right-to-left output
+	BidiIsolate bool `json:"bidi_isolate"`
+	// Calendar for displayed and logged timestamps: gregorian, jalali or both
+	TimeCalendar string `json:"time_calendar"`
+	// Timezone for displayed timestamps (empty = local, Asia/Tehran for jalali)
+	TimeZone string `json:"time_zone"`
+	// Per-run output: console, json, ndjson or csv
+	OutputFormat string `json:"output_format"`
+	// Log file path (empty to disable, "stdout"/"stderr" for the console)
+	LogFile string `json:"log_file"`
+	// Log level: debug, info, warn, error
+	LogLevel string `json:"log_level"`
+	// Log format: text or json
+	LogFormat string `json:"log_format"`
+	// Rotate the log file when it exceeds this size in MB (0 = never)
+	LogMaxSizeMB int `json:"log_max_size_mb"`
+	// Rotate the log file after this duration (0 = never)
+	LogRotateInterval time.Duration `json:"-"`
+	// Number of rotated log files to keep (0 = keep all)
+	LogMaxBackups int `json:"log_max_backups"`
+	// Compress rotated log files with gzip
+	LogCompress bool `json:"log_compress"`
 	// Shutdown timeout duration
-	ShutdownTimeout time.Duration
+	ShutdownTimeout time.Duration `json:"-"`
+	// HTTP listen address used in serve mode
+	HTTPAddr string `json:"http_addr"`
+	// How long /readyz reports shutting_down before the server stops on
+	// shutdown, so load balancers stop sending requests first
+	HTTPDrain time.Duration `json:"-"`
+	// Maximum data age before health checks fail (0 = derived from CheckInterval)
+	HealthMaxStaleness time.Duration `json:"-"`
+	// Watchlist of symbols with per-symbol overrides (empty = Symbol only)
+	Watchlist []WatchItem `json:"watchlist"`
+
+	// Sources records where every setting came from, by setting key
+	Sources map[string]string `json:"-"`
+
+	// envErrs are the environment variables that did not parse
+	envErrs []error
 }
 
-// DefaultConfig returns default configuration
+// DefaultConfig returns default configuration overridden by environment
+// variables. Use Load to read a config file as well.
 func DefaultConfig() *Config {
-	cfg := &Config{
+	// Without a file Load cannot fail
+	cfg, _ := Load("")
+	return cfg
+}
+
+// defaults returns the built-in configuration
+func defaults() *Config {
+	return &Config{
 		Symbol:              "GC=F",
 		Interval:            "1h",
 		Range:               "7d",
 		CheckInterval:       1 * time.Minute,
 		RSIPeriod:           14,
-		MACDFastPeriod:      12,
-		MACDSlowPeriod:      26,
-		MACDSignalPeriod:    9,
+		MACDFastPeriod:      8,
+		MACDSlowPeriod:      21,
+		MACDSignalPeriod:    5,
 		ATRPeriod:           14,
 		RSIBuyLower:         40,
 		RSIBuyUpper:         55,
 		RSISellThreshold:    65,
 		EnableNotifications: false,
+		NotifyWebhookURL:    "",
+		NotifyQueueFile:     "",
+		NotifyRatePerMinute: 6,
+		AlertsFile:          "",
+		StateFile:           "",
+		Lang:                "",
+		BidiIsolate:         true,
+		TimeCalendar:        "gregorian",
+		TimeZone:            "",
+		OutputFormat:        "console",
 		LogFile:             "",
+		LogLevel:            "info",
+		LogFormat:           "text",
+		LogMaxSizeMB:        10,
+		LogRotateInterval:   24 * time.Hour,
+		LogMaxBackups:       7,
+		LogCompress:         true,
 		ShutdownTimeout:     5 * time.Second,
+		HTTPAddr:            ":8080",
+		HTTPDrain:           2 * time.Second,
 	}
-
-	// Override with environment variables if present
-	loadFromEnv(cfg)
-
-	return cfg
 }
 
-// loadFromEnv loads configuration from environment variables
+// loadFromEnv loads configuration from environment variables. Values that
+// do not parse keep the previous setting and are reported by Validate.
 func loadFromEnv(cfg *Config) {
+	cfg.envErrs = nil
+
 	if symbol := os.Getenv("SYMBOL"); symbol != "" {
 		cfg.Symbol = symbol
 	}
@@ -76,45 +150,112 @@ func loadFromEnv(cfg *Config) {
 	if rangeVal := os.Getenv("RANGE"); rangeVal != "" {
 		cfg.Range = rangeVal
 	}
-	if checkInterval := os.Getenv("CHECK_INTERVAL_MINUTES"); checkInterval != "" {
-		if minutes, err := strconv.Atoi(checkInterval); err == nil {
-			cfg.CheckInterval = time.Duration(minutes) * time.Minute
+	if watchlist := os.Getenv("WATCHLIST"); watchlist != "" {
+		cfg.Watchlist = nil
+		for _, symbol := range strings.Split(watchlist, ",") {
+			if symbol = strings.TrimSpace(symbol); symbol != "" {
+				cfg.Watchlist = append(cfg.Watchlist, WatchItem{Symbol: symbol})
+			}
 		}
 	}
-	if rsiPeriod := os.Getenv("RSI_PERIOD"); rsiPeriod != "" {
-		if period, err := strconv.Atoi(rsiPeriod); err == nil {
-			cfg.RSIPeriod = period
-		}
+	cfg.envDuration("CHECK_INTERVAL_MINUTES", time.Minute, &cfg.CheckInterval)
+	cfg.envInt("RSI_PERIOD", &cfg.RSIPeriod)
+	cfg.envInt("MACD_FAST_PERIOD", &cfg.MACDFastPeriod)
+	cfg.envInt("MACD_SLOW_PERIOD", &cfg.MACDSlowPeriod)
+	cfg.envInt("MACD_SIGNAL_PERIOD", &cfg.MACDSignalPeriod)
+	cfg.envInt("ATR_PERIOD", &cfg.ATRPeriod)
+	cfg.envFloat("RSI_BUY_LOWER", &cfg.RSIBuyLower)
+	cfg.envFloat("RSI_BUY_UPPER", &cfg.RSIBuyUpper)
+	cfg.envFloat("RSI_SELL_THRESHOLD", &cfg.RSISellThreshold)
+	if alertsFile := os.Getenv("ALERTS_FILE"); alertsFile != "" {
+		cfg.AlertsFile = alertsFile
 	}
-	if atrPeriod := os.Getenv("ATR_PERIOD"); atrPeriod != "" {
-		if period, err := strconv.Atoi(atrPeriod); err == nil {
-			cfg.ATRPeriod = period
-		}
+	if stateFile := os.Getenv("STATE_FILE"); stateFile != "" {
+		cfg.StateFile = stateFile
 	}
-	if rsiBuyLower := os.Getenv("RSI_BUY_LOWER"); rsiBuyLower != "" {
-		if val, err := strconv.ParseFloat(rsiBuyLower, 64); err == nil {
-			cfg.RSIBuyLower = val
-		}
+	if lang := os.Getenv("UI_LANG"); lang != "" {
+		cfg.Lang = lang
 	}
-	if rsiBuyUpper := os.Getenv("RSI_BUY_UPPER"); rsiBuyUpper != "" {
-		if val, err := strconv.ParseFloat(rsiBuyUpper, 64); err == nil {
-			cfg.RSIBuyUpper = val
-		}
+	cfg.envBool("BIDI_ISOLATE", &cfg.BidiIsolate)
+	if timeCalendar := os.Getenv("TIME_CALENDAR"); timeCalendar != "" {
+		cfg.TimeCalendar = timeCalendar
 	}
-	if rsiSellThreshold := os.Getenv("RSI_SELL_THRESHOLD"); rsiSellThreshold != "" {
-		if val, err := strconv.ParseFloat(rsiSellThreshold, 64); err == nil {
-			cfg.RSISellThreshold = val
-		}
+	if timeZone := os.Getenv("TIME_ZONE"); timeZone != "" {
+		cfg.TimeZone = timeZone
+	}
+	if outputFormat := os.Getenv("OUTPUT_FORMAT"); outputFormat != "" {
+		cfg.OutputFormat = outputFormat
 	}
 	if logFile := os.Getenv("LOG_FILE"); logFile != "" {
 		cfg.LogFile = logFile
 	}
-	if enableNotif := os.Getenv("ENABLE_NOTIFICATIONS"); enableNotif != "" {
-		cfg.EnableNotifications = enableNotif == "1" || enableNotif == "true"
+	if logLevel := os.Getenv("LOG_LEVEL"); logLevel != "" {
+		cfg.LogLevel = logLevel
+	}
+	if logFormat := os.Getenv("LOG_FORMAT"); logFormat != "" {
+		cfg.LogFormat = logFormat
+	}
+	cfg.envInt("LOG_MAX_SIZE_MB", &cfg.LogMaxSizeMB)
+	cfg.envDuration("LOG_ROTATE_INTERVAL_HOURS", time.Hour, &cfg.LogRotateInterval)
+	cfg.envInt("LOG_MAX_BACKUPS", &cfg.LogMaxBackups)
+	cfg.envBool("LOG_COMPRESS", &cfg.LogCompress)
+	cfg.envBool("ENABLE_NOTIFICATIONS", &cfg.EnableNotifications)
+	if webhookURL := os.Getenv("NOTIFY_WEBHOOK_URL"); webhookURL != "" {
+		cfg.NotifyWebhookURL = webhookURL
 	}
-	if shutdownTimeout := os.Getenv("SHUTDOWN_TIMEOUT_SECONDS"); shutdownTimeout != "" {
-		if seconds, err := strconv.Atoi(shutdownTimeout); err == nil {
-			cfg.ShutdownTimeout = time.Duration(seconds) * time.Second
+	if queueFile := os.Getenv("NOTIFY_QUEUE_FILE"); queueFile != "" {
+		cfg.NotifyQueueFile = queueFile
+	}
+	cfg.envInt("NOTIFY_RATE_PER_MINUTE", &cfg.NotifyRatePerMinute)
+	cfg.envDuration("SHUTDOWN_TIMEOUT_SECONDS", time.Second, &cfg.ShutdownTimeout)
+	if httpAddr := os.Getenv("HTTP_ADDR"); httpAddr != "" {
+		cfg.HTTPAddr = httpAddr
+	}
+	cfg.envDuration("HTTP_DRAIN_SECONDS", time.Second, &cfg.HTTPDrain)
+	cfg.envDuration("HEALTH_MAX_STALENESS_SECONDS", time.Second, &cfg.HealthMaxStaleness)
+}
+
+func (c *Config) envInt(name string, dst *int) {
+	if s := os.Getenv(name); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			c.envErrs = append(c.envErrs, fmt.Errorf("%s=%q: not an integer", name, s))
+			return
+		}
+		*dst = v
+	}
+}
+
+func (c *Config) envFloat(name string, dst *float64) {
+	if s := os.Getenv(name); s != "" {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			c.envErrs = append(c.envErrs, fmt.Errorf("%s=%q: not a number", name, s))
+			return
+		}
+		*dst = v
+	}
+}
+
+// envDuration reads a whole number of units, e.g. minutes
+func (c *Config) envDuration(name string, unit time.Duration, dst *time.Duration) {
+	if s := os.Getenv(name); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			c.envErrs = append(c.envErrs, fmt.Errorf("%s=%q: not a whole number", name, s))
+			return
+		}
+		*dst = time.Duration(v) * unit
+	}
+}
+
+func (c *Config) envBool(name string, dst *bool) {
+	if s := os.Getenv(name); s != "" {
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			c.envErrs = append(c.envErrs, fmt.Errorf("%s=%q: use true or false", name, s))
+			return
 		}
+		*dst = v
 	}
 }
@@ -0,0 +1,366 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layers of the configuration, lowest precedence first
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+var sourceRank = map[string]int{SourceDefault: 0, SourceFile: 1, SourceEnv: 2, SourceFlag: 3}
+
+// Duration is a time.Duration written as a string like "90s" or "5m" in
+// config files
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"5m\", got %s", b)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// WatchItem is a watchlist symbol. Empty fields inherit the global settings.
+type WatchItem struct {
+	Symbol   string `json:"symbol"`
+	Interval string `json:"interval,omitempty"`
+	Range    string `json:"range,omitempty"`
+	// CheckInterval schedules this symbol independently of the global interval
+	CheckInterval Duration          `json:"check_interval,omitzero"`
+	Strategy      StrategyOverrides `json:"strategy,omitzero"`
+}
+
+// StrategyOverrides are per-symbol strategy parameters; zero values inherit
+type StrategyOverrides struct {
+	RSIPeriod        int     `json:"rsi_period,omitempty"`
+	MACDFastPeriod   int     `json:"macd_fast_period,omitempty"`
+	MACDSlowPeriod   int     `json:"macd_slow_period,omitempty"`
+	MACDSignalPeriod int     `json:"macd_signal_period,omitempty"`
+	ATRPeriod        int     `json:"atr_period,omitempty"`
+	RSIBuyLower      float64 `json:"rsi_buy_lower,omitempty"`
+	RSIBuyUpper      float64 `json:"rsi_buy_upper,omitempty"`
+	RSISellThreshold float64 `json:"rsi_sell_threshold,omitempty"`
+}
+
+// fileConfig is the layout of a config file: the setting keys of Config
+// with durations as strings
+type fileConfig struct {
+	*Config
+	CheckInterval      *Duration `json:"check_interval"`
+	LogRotateInterval  *Duration `json:"log_rotate_interval"`
+	ShutdownTimeout    *Duration `json:"shutdown_timeout"`
+	HTTPDrain          *Duration `json:"http_drain"`
+	HealthMaxStaleness *Duration `json:"health_max_staleness"`
+}
+
+// Load builds the configuration from the defaults, the config file at
+// path (skipped when empty) and the environment, in that order of
+// precedence. Sources records which layer set each value: a key present in
+// the file or a non-empty environment variable, even when it repeats the
+// value of a lower layer.
+func Load(path string) (*Config, error) {
+	cfg := defaults()
+	cfg.Sources = make(map[string]string)
+	for _, s := range cfg.Settings() {
+		cfg.Sources[s.Key] = SourceDefault
+	}
+
+	if path != "" {
+		keys, err := cfg.loadFile(path)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.SetSource(SourceFile+" "+path, keys...)
+	}
+
+	loadFromEnv(cfg)
+	var keys []string
+	for _, s := range cfg.Settings() {
+		if v, ok := os.LookupEnv(s.Env); ok && v != "" {
+			keys = append(keys, s.Key)
+		}
+	}
+	cfg.SetSource(SourceEnv, keys...)
+
+	return cfg, nil
+}
+
+// loadFile overlays the keys present in a config file and returns them
+func (c *Config) loadFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config file: %w", err)
+	}
+	if data, err = fileJSON(path, data); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	f := fileConfig{Config: c}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	for _, d := range []struct {
+		from *Duration
+		to   *time.Duration
+	}{
+		{f.CheckInterval, &c.CheckInterval},
+		{f.LogRotateInterval, &c.LogRotateInterval},
+		{f.ShutdownTimeout, &c.ShutdownTimeout},
+		{f.HTTPDrain, &c.HTTPDrain},
+		{f.HealthMaxStaleness, &c.HealthMaxStaleness},
+	} {
+		if d.from != nil {
+			*d.to = time.Duration(*d.from)
+		}
+	}
+
+	// The file decoded above, so its top level is an object
+	var present map[string]json.RawMessage
+	json.Unmarshal(data, &present)
+	keys := make([]string, 0, len(present))
+	for key := range present {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// fileJSON converts a YAML (.yaml, .yml) or TOML (.toml) config file to
+// JSON, so every format is decoded with the same checks. Other files are
+// JSON already.
+func fileJSON(path string, data []byte) ([]byte, error) {
+	var tree any
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		tree, err = parseYAML(data)
+	case ".toml":
+		tree, err = parseTOML(data)
+	default:
+		return data, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(tree)
+}
+
+// SetSource records that layer set the given setting keys, e.g. flags set
+// on the command line. The environment layer is recorded with the
+// variable of each key.
+func (c *Config) SetSource(layer string, keys ...string) {
+	if c.Sources == nil {
+		c.Sources = make(map[string]string)
+	}
+	env := make(map[string]string)
+	for _, s := range c.Settings() {
+		env[s.Key] = s.Env
+	}
+	for _, key := range keys {
+		if layer == SourceEnv {
+			c.Sources[key] = SourceEnv + " " + env[key]
+		} else {
+			c.Sources[key] = layer
+		}
+	}
+}
+
+// Source returns the layer a setting came from, e.g. "file config.json"
+// or "env RSI_PERIOD"
+func (c *Config) Source(key string) string {
+	if src, ok := c.Sources[key]; ok {
+		return src
+	}
+	return SourceDefault
+}
+
+// rank orders the layer of a setting by precedence
+func (c *Config) rank(key string) int {
+	layer, _, _ := strings.Cut(c.Source(key), " ")
+	return sourceRank[layer]
+}
+
+// Targets returns one configuration per watchlist symbol with its
+// overrides applied. A symbol set by a higher-precedence layer than the
+// watchlist (e.g. -symbol over a file watchlist) replaces the watchlist,
+// keeping the overrides of its entry if it has one.
+func (c *Config) Targets() []*Config {
+	if len(c.Watchlist) == 0 {
+		return []*Config{c}
+	}
+
+	if c.rank("symbol") > c.rank("watchlist") {
+		for _, w := range c.Watchlist {
+			if w.Symbol == c.Symbol {
+				return []*Config{c.target(w)}
+			}
+		}
+		return []*Config{c}
+	}
+
+	targets := make([]*Config, 0, len(c.Watchlist))
+	for _, w := range c.Watchlist {
+		targets = append(targets, c.target(w))
+	}
+	return targets
+}
+
+// target returns a copy of c for one watchlist entry. An entry only
+// overrides settings from layers that do not outrank the watchlist, so
+// -interval still applies to every symbol of a file watchlist.
+func (c *Config) target(w WatchItem) *Config {
+	t := *c
+	t.Watchlist = nil
+	t.Symbol = w.Symbol
+
+	rank := c.rank("watchlist")
+	set := func(key string, apply func()) {
+		if c.rank(key) <= rank {
+			apply()
+		}
+	}
+	o := w.Strategy
+	if w.Interval != "" {
+		set("interval", func() { t.Interval = w.Interval })
+	}
+	if w.Range != "" {
+		set("range", func() { t.Range = w.Range })
+	}
+	if w.CheckInterval > 0 {
+		set("check_interval", func() { t.CheckInterval = time.Duration(w.CheckInterval) })
+	}
+	if o.RSIPeriod != 0 {
+		set("rsi_period", func() { t.RSIPeriod = o.RSIPeriod })
+	}
+	if o.MACDFastPeriod != 0 {
+		set("macd_fast_period", func() { t.MACDFastPeriod = o.MACDFastPeriod })
+	}
+	if o.MACDSlowPeriod != 0 {
+		set("macd_slow_period", func() { t.MACDSlowPeriod = o.MACDSlowPeriod })
+	}
+	if o.MACDSignalPeriod != 0 {
+		set("macd_signal_period", func() { t.MACDSignalPeriod = o.MACDSignalPeriod })
+	}
+	if o.ATRPeriod != 0 {
+		set("atr_period", func() { t.ATRPeriod = o.ATRPeriod })
+	}
+	if o.RSIBuyLower != 0 {
+		set("rsi_buy_lower", func() { t.RSIBuyLower = o.RSIBuyLower })
+	}
+	if o.RSIBuyUpper != 0 {
+		set("rsi_buy_upper", func() { t.RSIBuyUpper = o.RSIBuyUpper })
+	}
+	if o.RSISellThreshold != 0 {
+		set("rsi_sell_threshold", func() { t.RSISellThreshold = o.RSISellThreshold })
+	}
+	return &t
+}
+
+// Change is a setting that differs between two configurations
+type Change struct {
+	Key string
+	Old string
+	New string
+}
+
+// Diff lists the settings that differ from old to new, in file order
+func Diff(old, new *Config) []Change {
+	before := make(map[string]string)
+	for _, s := range old.Settings() {
+		before[s.Key] = s.Value
+	}
+
+	var changes []Change
+	for _, s := range new.Settings() {
+		if before[s.Key] != s.Value {
+			changes = append(changes, Change{s.Key, before[s.Key], s.Value})
+		}
+	}
+	return changes
+}
+
+// Setting is one configuration value for display
+type Setting struct {
+	// Key in the config file
+	Key string
+	// Env is the environment variable
+	Env   string
+	Value string
+}
+
+// Settings lists every setting in file order
+func (c *Config) Settings() []Setting {
+	itoa := strconv.Itoa
+	ftoa := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	btoa := strconv.FormatBool
+
+	watchlist := ""
+	if len(c.Watchlist) > 0 {
+		b, _ := json.Marshal(c.Watchlist)
+		watchlist = string(b)
+	}
+
+	return []Setting{
+		{"symbol", "SYMBOL", c.Symbol},
+		{"interval", "INTERVAL", c.Interval},
+		{"range", "RANGE", c.Range},
+		{"watchlist", "WATCHLIST", watchlist},
+		{"check_interval", "CHECK_INTERVAL_MINUTES", c.CheckInterval.String()},
+		{"rsi_period", "RSI_PERIOD", itoa(c.RSIPeriod)},
+		{"macd_fast_period", "MACD_FAST_PERIOD", itoa(c.MACDFastPeriod)},
+		{"macd_slow_period", "MACD_SLOW_PERIOD", itoa(c.MACDSlowPeriod)},
+		{"macd_signal_period", "MACD_SIGNAL_PERIOD", itoa(c.MACDSignalPeriod)},
+		{"atr_period", "ATR_PERIOD", itoa(c.ATRPeriod)},
+		{"rsi_buy_lower", "RSI_BUY_LOWER", ftoa(c.RSIBuyLower)},
+		{"rsi_buy_upper", "RSI_BUY_UPPER", ftoa(c.RSIBuyUpper)},
+		{"rsi_sell_threshold", "RSI_SELL_THRESHOLD", ftoa(c.RSISellThreshold)},
+		{"enable_notifications", "ENABLE_NOTIFICATIONS", btoa(c.EnableNotifications)},
+		{"notify_webhook_url", "NOTIFY_WEBHOOK_URL", c.NotifyWebhookURL},
+		{"notify_queue_file", "NOTIFY_QUEUE_FILE", c.NotifyQueueFile},
+		{"notify_rate_per_minute", "NOTIFY_RATE_PER_MINUTE", itoa(c.NotifyRatePerMinute)},
+		{"alerts_file", "ALERTS_FILE", c.AlertsFile},
+		{"state_file", "STATE_FILE", c.StateFile},
+		{"lang", "UI_LANG", c.Lang},
+		{"bidi_isolate", "BIDI_ISOLATE", btoa(c.BidiIsolate)},
+		{"time_calendar", "TIME_CALENDAR", c.TimeCalendar},
+		{"time_zone", "TIME_ZONE", c.TimeZone},
+		{"output_format", "OUTPUT_FORMAT", c.OutputFormat},
+		{"log_file", "LOG_FILE", c.LogFile},
+		{"log_level", "LOG_LEVEL", c.LogLevel},
+		{"log_format", "LOG_FORMAT", c.LogFormat},
+		{"log_max_size_mb", "LOG_MAX_SIZE_MB", itoa(c.LogMaxSizeMB)},
+		{"log_rotate_interval", "LOG_ROTATE_INTERVAL_HOURS", c.LogRotateInterval.String()},
+		{"log_max_backups", "LOG_MAX_BACKUPS", itoa(c.LogMaxBackups)},
+		{"log_compress", "LOG_COMPRESS", btoa(c.LogCompress)},
+		{"shutdown_timeout", "SHUTDOWN_TIMEOUT_SECONDS", c.ShutdownTimeout.String()},
+		{"http_addr", "HTTP_ADDR", c.HTTPAddr},
+		{"http_drain", "HTTP_DRAIN_SECONDS", c.HTTPDrain.String()},
+		{"health_max_staleness", "HEALTH_MAX_STALENESS_SECONDS", c.HealthMaxStaleness.String()},
+	}
+}
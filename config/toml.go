@@ -0,0 +1,339 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type tomlParser struct {
+	s   string
+	pos int
+}
+
+// parseTOML decodes the TOML a config file needs: key/value pairs with
+// strings, numbers, booleans, arrays and inline tables, [tables] and
+// [[arrays of tables]]. Dates and multi-line strings are rejected.
+func parseTOML(data []byte) (map[string]any, error) {
+	p := &tomlParser{s: strings.ReplaceAll(string(data), "\r\n", "\n")}
+	root := make(map[string]any)
+	cur := root
+	defined := make(map[string]bool)
+
+	for {
+		p.skipBlank(true)
+		if p.pos == len(p.s) {
+			return root, nil
+		}
+
+		if p.s[p.pos] == '[' {
+			array := strings.HasPrefix(p.s[p.pos:], "[[")
+			p.pos++
+			if array {
+				p.pos++
+			}
+			path, err := p.key()
+			if err != nil {
+				return nil, err
+			}
+			closing := "]"
+			if array {
+				closing = "]]"
+			}
+			p.skipBlank(false)
+			if !strings.HasPrefix(p.s[p.pos:], closing) {
+				return nil, p.errorf("expected %q after the table name", closing)
+			}
+			p.pos += len(closing)
+
+			parent, err := p.table(root, path[:len(path)-1])
+			if err != nil {
+				return nil, err
+			}
+			name := path[len(path)-1]
+			full := strings.Join(path, "\x00")
+			if array {
+				// Every element defines its own subtables
+				for t := range defined {
+					if strings.HasPrefix(t, full+"\x00") {
+						delete(defined, t)
+					}
+				}
+				list, ok := parent[name].([]any)
+				if !ok && parent[name] != nil {
+					return nil, p.errorf("%s is not an array of tables", strings.Join(path, "."))
+				}
+				cur = make(map[string]any)
+				parent[name] = append(list, cur)
+			} else {
+				if defined[full] {
+					return nil, p.errorf("table [%s] is defined twice", strings.Join(path, "."))
+				}
+				defined[full] = true
+				if cur, err = p.table(parent, path[len(path)-1:]); err != nil {
+					return nil, err
+				}
+			}
+		} else if err := p.keyValue(cur); err != nil {
+			return nil, err
+		}
+
+		// Nothing but a comment may follow on the line
+		p.skipBlank(false)
+		if p.pos < len(p.s) && p.s[p.pos] != '\n' {
+			return nil, p.errorf("unexpected %q", p.rest())
+		}
+	}
+}
+
+// table returns the table at path below t, creating missing tables. An
+// array of tables stands for its last element.
+func (p *tomlParser) table(t map[string]any, path []string) (map[string]any, error) {
+	for _, name := range path {
+		switch v := t[name].(type) {
+		case nil:
+			next := make(map[string]any)
+			t[name] = next
+			t = next
+		case map[string]any:
+			t = v
+		case []any:
+			last, ok := v[len(v)-1].(map[string]any)
+			if !ok {
+				return nil, p.errorf("%s is not a table", name)
+			}
+			t = last
+		default:
+			return nil, p.errorf("%s is already a value", name)
+		}
+	}
+	return t, nil
+}
+
+// keyValue parses "key = value" into t
+func (p *tomlParser) keyValue(t map[string]any) error {
+	path, err := p.key()
+	if err != nil {
+		return err
+	}
+	p.skipBlank(false)
+	if p.pos == len(p.s) || p.s[p.pos] != '=' {
+		return p.errorf("expected \"=\" after %s", strings.Join(path, "."))
+	}
+	p.pos++
+
+	v, err := p.value()
+	if err != nil {
+		return err
+	}
+	if t, err = p.table(t, path[:len(path)-1]); err != nil {
+		return err
+	}
+	name := path[len(path)-1]
+	if _, dup := t[name]; dup {
+		return p.errorf("duplicate key %s", strings.Join(path, "."))
+	}
+	t[name] = v
+	return nil
+}
+
+// key parses a bare, quoted or dotted key
+func (p *tomlParser) key() ([]string, error) {
+	var path []string
+	for {
+		p.skipBlank(false)
+		if p.pos == len(p.s) {
+			return nil, p.errorf("expected a key")
+		}
+		switch c := p.s[p.pos]; {
+		case c == '"' || c == '\'':
+			s, err := p.str()
+			if err != nil {
+				return nil, err
+			}
+			path = append(path, s)
+		default:
+			start := p.pos
+			for p.pos < len(p.s) && isBareKey(p.s[p.pos]) {
+				p.pos++
+			}
+			if p.pos == start {
+				return nil, p.errorf("expected a key, got %q", p.rest())
+			}
+			path = append(path, p.s[start:p.pos])
+		}
+		p.skipBlank(false)
+		if p.pos == len(p.s) || p.s[p.pos] != '.' {
+			return path, nil
+		}
+		p.pos++
+	}
+}
+
+func isBareKey(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+func (p *tomlParser) value() (any, error) {
+	p.skipBlank(false)
+	if p.pos == len(p.s) || p.s[p.pos] == '\n' {
+		return nil, p.errorf("expected a value")
+	}
+
+	switch p.s[p.pos] {
+	case '"', '\'':
+		return p.str()
+	case '[':
+		p.pos++
+		list := make([]any, 0)
+		for {
+			p.skipBlank(true)
+			if p.pos < len(p.s) && p.s[p.pos] == ']' {
+				p.pos++
+				return list, nil
+			}
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+			p.skipBlank(true)
+			if p.pos < len(p.s) && p.s[p.pos] == ',' {
+				p.pos++
+			} else if p.pos == len(p.s) || p.s[p.pos] != ']' {
+				return nil, p.errorf("expected \",\" or \"]\" in array")
+			}
+		}
+	case '{':
+		p.pos++
+		t := make(map[string]any)
+		for {
+			p.skipBlank(false)
+			if p.pos < len(p.s) && p.s[p.pos] == '}' {
+				p.pos++
+				return t, nil
+			}
+			if len(t) > 0 {
+				if p.pos == len(p.s) || p.s[p.pos] != ',' {
+					return nil, p.errorf("expected \",\" or \"}\" in inline table")
+				}
+				p.pos++
+			}
+			if err := p.keyValue(t); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte(" \t\n,]}#", p.s[p.pos]) < 0 {
+		p.pos++
+	}
+	word := p.s[start:p.pos]
+	switch word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if strings.ContainsAny(word, ":T") || strings.Count(word, "-") > 1 {
+		return nil, p.errorf("%s: dates and times are not supported, quote the value", word)
+	}
+	num := strings.ReplaceAll(word, "_", "")
+	if n, err := strconv.ParseInt(num, 10, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(num, 64); err == nil && !strings.ContainsAny(num, "xXpP") {
+		return f, nil
+	}
+	return nil, p.errorf("invalid value %q, quote strings", word)
+}
+
+// str parses a basic "..." or literal '...' string on one line
+func (p *tomlParser) str() (string, error) {
+	quote := p.s[p.pos]
+	if strings.HasPrefix(p.s[p.pos:], strings.Repeat(string(quote), 3)) {
+		return "", p.errorf("multi-line strings are not supported")
+	}
+	p.pos++
+
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		switch {
+		case c == '\n':
+			return "", p.errorf("missing closing quote")
+		case c == quote:
+			p.pos++
+			return b.String(), nil
+		case c == '\\' && quote == '"':
+			if err := p.escape(&b); err != nil {
+				return "", err
+			}
+			continue
+		default:
+			b.WriteByte(c)
+		}
+		p.pos++
+	}
+	return "", p.errorf("missing closing quote")
+}
+
+// escape decodes the escape sequence at the current position
+func (p *tomlParser) escape(b *strings.Builder) error {
+	if p.pos+1 == len(p.s) {
+		return p.errorf("missing closing quote")
+	}
+	c := p.s[p.pos+1]
+	p.pos += 2
+	simple := map[byte]byte{'b': '\b', 't': '\t', 'n': '\n', 'f': '\f', 'r': '\r', '"': '"', '\\': '\\'}
+	if r, ok := simple[c]; ok {
+		b.WriteByte(r)
+		return nil
+	}
+
+	size := map[byte]int{'u': 4, 'U': 8}[c]
+	if size == 0 || p.pos+size > len(p.s) {
+		return p.errorf("invalid escape \\%c", c)
+	}
+	code, err := strconv.ParseUint(p.s[p.pos:p.pos+size], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(code)) {
+		return p.errorf("invalid escape \\%c%s", c, p.s[p.pos:p.pos+size])
+	}
+	b.WriteRune(rune(code))
+	p.pos += size
+	return nil
+}
+
+// skipBlank skips spaces and comments, and newlines too when lines is set
+func (p *tomlParser) skipBlank(lines bool) {
+	for p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case ' ', '\t':
+			p.pos++
+		case '\n':
+			if !lines {
+				return
+			}
+			p.pos++
+		case '#':
+			for p.pos < len(p.s) && p.s[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// rest returns the remainder of the current line
+func (p *tomlParser) rest() string {
+	line, _, _ := strings.Cut(p.s[p.pos:], "\n")
+	return line
+}
+
+func (p *tomlParser) errorf(format string, args ...any) error {
+	line := strings.Count(p.s[:p.pos], "\n") + 1
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
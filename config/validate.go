@@ -0,0 +1,186 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"strings"
+
+	"gold-analyzer/i18n"
+)
+
+var outputFormats = []string{"console", "json", "ndjson", "csv", "tui"}
+
+// problem is one invalid setting
+type problem struct {
+	key   string
+	value any
+	msg   string
+}
+
+// Validate checks every setting, including the overrides of each watchlist
+// symbol, and returns all problems joined, or nil
+func (c *Config) Validate() error {
+	errs := append([]error(nil), c.envErrs...)
+
+	global := c.check()
+	reported := make(map[problem]bool)
+	for _, p := range global {
+		reported[p] = true
+		errs = append(errs, fmt.Errorf("%s = %v (%s): %s", p.key, p.value, c.Source(p.key), p.msg))
+	}
+
+	seen := make(map[string]bool)
+	for _, w := range c.Watchlist {
+		if w.Symbol == "" {
+			errs = append(errs, fmt.Errorf("watchlist (%s): entry without a symbol", c.Source("watchlist")))
+			continue
+		}
+		if seen[w.Symbol] {
+			errs = append(errs, fmt.Errorf("watchlist (%s): %s is listed twice", c.Source("watchlist"), w.Symbol))
+		}
+		seen[w.Symbol] = true
+
+		// Problems inherited from the global settings are reported once
+		for _, p := range c.target(w).checkStrategy() {
+			if !reported[p] {
+				errs = append(errs, fmt.Errorf("watchlist %s: %s = %v: %s", w.Symbol, p.key, p.value, p.msg))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// check returns the problems of the whole configuration
+func (c *Config) check() []problem {
+	ps := c.checkStrategy()
+	add := func(key string, value any, format string, args ...any) {
+		ps = append(ps, problem{key, value, fmt.Sprintf(format, args...)})
+	}
+
+	if c.NotifyRatePerMinute < 0 {
+		add("notify_rate_per_minute", c.NotifyRatePerMinute, "must not be negative (0 = unlimited)")
+	}
+	if c.NotifyWebhookURL != "" {
+		if u, err := url.Parse(c.NotifyWebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			// The URL usually embeds a token, so it is not echoed
+			add("notify_webhook_url", "****", "must be an http or https URL")
+		}
+	}
+	if c.Lang != "" && !i18n.Supported(c.Lang) {
+		add("lang", c.Lang, "unsupported language (use fa or en)")
+	}
+	calendar, err := i18n.ParseCalendar(c.TimeCalendar)
+	if err != nil {
+		add("time_calendar", c.TimeCalendar, "use gregorian, jalali or both")
+	} else if _, err := i18n.LoadLocation(c.TimeZone, calendar); err != nil {
+		add("time_zone", c.TimeZone, "unknown timezone (use an IANA name like Asia/Tehran)")
+	}
+	if c.OutputFormat != "" && !oneOf(c.OutputFormat, outputFormats...) {
+		add("output_format", c.OutputFormat, "use %s", strings.Join(outputFormats, ", "))
+	}
+	var level slog.Level
+	if c.LogLevel != "" && level.UnmarshalText([]byte(c.LogLevel)) != nil {
+		add("log_level", c.LogLevel, "use debug, info, warn or error")
+	}
+	if !oneOf(c.LogFormat, "", "text", "json") {
+		add("log_format", c.LogFormat, "use text or json")
+	}
+	if c.LogMaxSizeMB < 0 {
+		add("log_max_size_mb", c.LogMaxSizeMB, "must not be negative (0 = never rotate by size)")
+	}
+	if c.LogRotateInterval < 0 {
+		add("log_rotate_interval", c.LogRotateInterval, "must not be negative (0 = never rotate by age)")
+	}
+	if c.LogMaxBackups < 0 {
+		add("log_max_backups", c.LogMaxBackups, "must not be negative (0 = keep all)")
+	}
+	if c.ShutdownTimeout <= 0 {
+		add("shutdown_timeout", c.ShutdownTimeout, "must be positive")
+	}
+	if _, _, err := net.SplitHostPort(c.HTTPAddr); err != nil {
+		add("http_addr", c.HTTPAddr, "use host:port or :port")
+	}
+	if c.HTTPDrain < 0 || c.HTTPDrain >= c.ShutdownTimeout && c.ShutdownTimeout > 0 {
+		add("http_drain", c.HTTPDrain, "must not be negative and must be less than shutdown_timeout (%s)", c.ShutdownTimeout)
+	}
+	if c.HealthMaxStaleness < 0 {
+		add("health_max_staleness", c.HealthMaxStaleness, "must not be negative (0 = derived from check_interval)")
+	}
+	return ps
+}
+
+// checkStrategy returns the problems of the settings a watchlist symbol
+// can override
+func (c *Config) checkStrategy() []problem {
+	var ps []problem
+	add := func(key string, value any, format string, args ...any) {
+		ps = append(ps, problem{key, value, fmt.Sprintf(format, args...)})
+	}
+
+	if strings.TrimSpace(c.Symbol) == "" {
+		add("symbol", `""`, "must not be empty")
+	}
+	_, validInterval := IntervalDuration(c.Interval)
+	if !validInterval {
+		add("interval", c.Interval, IntervalHint)
+	}
+	days, validRange := RangeDays(c.Range)
+	if !validRange {
+		add("range", c.Range, RangeHint)
+	}
+	if limit, intraday := IntradayDays(c.Interval); validInterval && validRange && intraday && days > limit {
+		add("range", c.Range, "%s candles are only available for the last %d days", c.Interval, limit)
+	}
+	if c.CheckInterval <= 0 {
+		add("check_interval", c.CheckInterval, "must be positive")
+	}
+
+	for _, p := range []struct {
+		key   string
+		value int
+	}{
+		{"rsi_period", c.RSIPeriod},
+		{"macd_fast_period", c.MACDFastPeriod},
+		{"macd_slow_period", c.MACDSlowPeriod},
+		{"macd_signal_period", c.MACDSignalPeriod},
+		{"atr_period", c.ATRPeriod},
+	} {
+		if p.value < 1 {
+			add(p.key, p.value, "must be at least 1")
+		}
+	}
+	if c.MACDFastPeriod >= c.MACDSlowPeriod && c.MACDFastPeriod > 0 {
+		add("macd_fast_period", c.MACDFastPeriod, "must be less than macd_slow_period (%d)", c.MACDSlowPeriod)
+	}
+
+	for _, t := range []struct {
+		key   string
+		value float64
+	}{
+		{"rsi_buy_lower", c.RSIBuyLower},
+		{"rsi_buy_upper", c.RSIBuyUpper},
+		{"rsi_sell_threshold", c.RSISellThreshold},
+	} {
+		if t.value < 0 || t.value > 100 {
+			add(t.key, t.value, "RSI thresholds must be between 0 and 100")
+		}
+	}
+	if c.RSIBuyLower >= c.RSIBuyUpper {
+		add("rsi_buy_lower", c.RSIBuyLower, "must be less than rsi_buy_upper (%g)", c.RSIBuyUpper)
+	}
+	return ps
+}
+
+func oneOf(s string, values ...string) bool {
+	s = strings.ToLower(s)
+	for _, v := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
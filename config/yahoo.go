@@ -0,0 +1,66 @@
+package config
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// intervals are the candle lengths of the intervals supported by the Yahoo
+// chart API
+var intervals = map[string]time.Duration{
+	"1m": time.Minute, "2m": 2 * time.Minute, "5m": 5 * time.Minute, "15m": 15 * time.Minute,
+	"30m": 30 * time.Minute, "60m": time.Hour, "90m": 90 * time.Minute, "1h": time.Hour,
+	"1d": 24 * time.Hour, "5d": 5 * 24 * time.Hour, "1wk": 7 * 24 * time.Hour,
+	"1mo": 30 * 24 * time.Hour, "3mo": 90 * 24 * time.Hour,
+}
+
+// intradayDays is how far back Yahoo serves each intraday interval
+var intradayDays = map[string]int{
+	"1m": 7, "2m": 60, "5m": 60, "15m": 60, "30m": 60, "90m": 60,
+	"60m": 730, "1h": 730,
+}
+
+var rangePattern = regexp.MustCompile(`^([1-9][0-9]*)(d|wk|mo|y)$`)
+
+// Messages for invalid intervals and ranges
+const (
+	IntervalHint = "use 1m, 2m, 5m, 15m, 30m, 60m, 90m, 1h, 1d, 5d, 1wk, 1mo or 3mo"
+	RangeHint    = "use a period like 7d, 2wk, 3mo or 1y, or ytd or max"
+)
+
+// IntervalDuration returns the candle length of a Yahoo interval, and
+// false for an unsupported interval
+func IntervalDuration(interval string) (time.Duration, bool) {
+	d, ok := intervals[interval]
+	return d, ok
+}
+
+// IntradayDays returns how far back Yahoo serves an intraday interval, and
+// false for daily and longer intervals
+func IntradayDays(interval string) (int, bool) {
+	days, ok := intradayDays[interval]
+	return days, ok
+}
+
+// RangeDays returns the approximate length of a Yahoo range in days, and
+// false for an invalid range
+func RangeDays(r string) (int, bool) {
+	switch r {
+	case "ytd":
+		return 365, true
+	case "max":
+		return 1 << 30, true
+	}
+
+	m := rangePattern.FindStringSubmatch(r)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, false
+	}
+	unit := map[string]int{"d": 1, "wk": 7, "mo": 30, "y": 365}[m[2]]
+	return n * unit, true
+}
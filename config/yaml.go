@@ -0,0 +1,385 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// yamlLine is a non-blank line of a YAML file without its comment
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// parseYAML decodes the block-style YAML a config file needs: mappings,
+// sequences, flow collections like [a, b] and {k: v}, and scalars. Anchors,
+// tags, multi-line scalars and multiple documents are rejected.
+func parseYAML(data []byte) (any, error) {
+	p := &yamlParser{}
+	for i, raw := range strings.Split(string(data), "\n") {
+		text := strings.TrimRight(stripComment(strings.TrimRight(raw, "\r"), true), " \t")
+		body := strings.TrimLeft(text, " ")
+		if body == "" {
+			continue
+		}
+		if strings.HasPrefix(body, "\t") {
+			return nil, fmt.Errorf("line %d: indent with spaces, not tabs", i+1)
+		}
+		if body == "---" && len(p.lines) == 0 {
+			continue
+		}
+		if body == "---" || body == "..." {
+			return nil, fmt.Errorf("line %d: only one document is supported", i+1)
+		}
+		p.lines = append(p.lines, yamlLine{num: i + 1, indent: len(text) - len(body), text: body})
+	}
+	if len(p.lines) == 0 {
+		return map[string]any{}, nil
+	}
+
+	v, err := p.block(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", p.lines[p.pos].num)
+	}
+	return v, nil
+}
+
+// block parses the mapping or sequence starting at the current line
+func (p *yamlParser) block(indent int) (any, error) {
+	if isSeqItem(p.lines[p.pos].text) {
+		return p.sequence(indent)
+	}
+	return p.mapping(indent)
+}
+
+func (p *yamlParser) mapping(indent int) (any, error) {
+	m := make(map[string]any)
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent < indent || l.indent == indent && isSeqItem(l.text) {
+			break
+		}
+		if l.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", l.num)
+		}
+
+		key, rest, ok := splitKey(l.text)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\", got %q", l.num, l.text)
+		}
+		if _, dup := m[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", l.num, key)
+		}
+		p.pos++
+
+		if rest != "" {
+			v, err := yamlValue(rest)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", l.num, err)
+			}
+			m[key] = v
+			continue
+		}
+
+		// The value is the nested block, if any; a sequence may start at
+		// the indentation of its key
+		m[key] = nil
+		if p.pos < len(p.lines) {
+			next := p.lines[p.pos]
+			if next.indent > indent || next.indent == indent && isSeqItem(next.text) {
+				v, err := p.block(next.indent)
+				if err != nil {
+					return nil, err
+				}
+				m[key] = v
+			}
+		}
+	}
+	return m, nil
+}
+
+func (p *yamlParser) sequence(indent int) (any, error) {
+	list := make([]any, 0)
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent < indent || l.indent == indent && !isSeqItem(l.text) {
+			break
+		}
+		if l.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", l.num)
+		}
+
+		rest := strings.TrimLeft(l.text[1:], " ")
+		if rest == "" {
+			// The item is the nested block
+			p.pos++
+			var v any
+			if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				var err error
+				if v, err = p.block(p.lines[p.pos].indent); err != nil {
+					return nil, err
+				}
+			}
+			list = append(list, v)
+			continue
+		}
+
+		// A mapping or sequence item starts on the dash line; continue it
+		// as a block indented to its first character
+		_, _, isKey := splitKey(rest)
+		if isKey || isSeqItem(rest) {
+			p.lines[p.pos] = yamlLine{num: l.num, indent: indent + len(l.text) - len(rest), text: rest}
+			v, err := p.block(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+			continue
+		}
+
+		v, err := yamlValue(rest)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", l.num, err)
+		}
+		list = append(list, v)
+		p.pos++
+	}
+	return list, nil
+}
+
+func isSeqItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitKey splits "key: value" at the first colon followed by a space or
+// the end of the line, outside quotes
+func splitKey(text string) (key, rest string, ok bool) {
+	if text == "" || strings.ContainsRune("[{", rune(text[0])) {
+		return "", "", false
+	}
+	quote := byte(0)
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && i == 0:
+			quote = c
+		case c == ':' && (i+1 == len(text) || text[i+1] == ' '):
+			k, err := yamlValue(strings.TrimSpace(text[:i]))
+			if err != nil || k == nil {
+				return "", "", false
+			}
+			return fmt.Sprint(k), strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// yamlValue parses a scalar or a flow collection
+func yamlValue(text string) (any, error) {
+	if text != "" && strings.ContainsRune("|>&*!%@`", rune(text[0])) {
+		return nil, fmt.Errorf("%q: anchors, tags and multi-line scalars are not supported", text)
+	}
+	f := &flowParser{s: text}
+	v, err := f.value(false)
+	if err != nil {
+		return nil, err
+	}
+	f.skipSpace()
+	if f.pos < len(f.s) {
+		return nil, fmt.Errorf("%q: unexpected %q", text, f.s[f.pos:])
+	}
+	return v, nil
+}
+
+// flowParser parses a YAML flow value on one line
+type flowParser struct {
+	s   string
+	pos int
+}
+
+func (f *flowParser) skipSpace() {
+	for f.pos < len(f.s) && f.s[f.pos] == ' ' {
+		f.pos++
+	}
+}
+
+// value parses the next value; inFlow is set inside [...] and {...},
+// where commas and brackets end plain scalars
+func (f *flowParser) value(inFlow bool) (any, error) {
+	f.skipSpace()
+	if f.pos == len(f.s) {
+		return nil, nil
+	}
+	switch f.s[f.pos] {
+	case '[':
+		return f.list()
+	case '{':
+		return f.object()
+	case '"', '\'':
+		return f.quoted()
+	}
+
+	start := f.pos
+	for f.pos < len(f.s) {
+		c := f.s[f.pos]
+		if inFlow && (c == ',' || c == ']' || c == '}' || c == ':' && (f.pos+1 == len(f.s) || f.s[f.pos+1] == ' ')) {
+			break
+		}
+		f.pos++
+	}
+	return yamlScalar(strings.TrimSpace(f.s[start:f.pos])), nil
+}
+
+func (f *flowParser) list() (any, error) {
+	f.pos++
+	list := make([]any, 0)
+	for {
+		f.skipSpace()
+		if f.pos < len(f.s) && f.s[f.pos] == ']' {
+			f.pos++
+			return list, nil
+		}
+		v, err := f.value(true)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+		if err := f.separator(']'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (f *flowParser) object() (any, error) {
+	f.pos++
+	m := make(map[string]any)
+	for {
+		f.skipSpace()
+		if f.pos < len(f.s) && f.s[f.pos] == '}' {
+			f.pos++
+			return m, nil
+		}
+		k, err := f.value(true)
+		if err != nil {
+			return nil, err
+		}
+		f.skipSpace()
+		if f.pos == len(f.s) || f.s[f.pos] != ':' {
+			return nil, fmt.Errorf("%q: expected \":\" after key %v", f.s, k)
+		}
+		f.pos++
+		key := fmt.Sprint(k)
+		if _, dup := m[key]; dup {
+			return nil, fmt.Errorf("%q: duplicate key %q", f.s, key)
+		}
+		if m[key], err = f.value(true); err != nil {
+			return nil, err
+		}
+		if err := f.separator('}'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// separator consumes the comma after an item, leaving the closing bracket
+func (f *flowParser) separator(closing byte) error {
+	f.skipSpace()
+	switch {
+	case f.pos == len(f.s):
+		return fmt.Errorf("%q: missing %q", f.s, closing)
+	case f.s[f.pos] == ',':
+		f.pos++
+	case f.s[f.pos] != closing:
+		return fmt.Errorf("%q: expected \",\" or %q", f.s, closing)
+	}
+	return nil
+}
+
+func (f *flowParser) quoted() (any, error) {
+	quote := f.s[f.pos]
+	for i := f.pos + 1; i < len(f.s); i++ {
+		switch {
+		case quote == '"' && f.s[i] == '\\':
+			i++
+		case quote == '\'' && f.s[i] == '\'' && i+1 < len(f.s) && f.s[i+1] == '\'':
+			i++
+		case f.s[i] == quote:
+			raw := f.s[f.pos : i+1]
+			f.pos = i + 1
+			if quote == '\'' {
+				return strings.ReplaceAll(raw[1:len(raw)-1], "''", "'"), nil
+			}
+			s, err := strconv.Unquote(raw)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid escape", raw)
+			}
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: missing closing quote", f.s[f.pos:])
+}
+
+// yamlScalar resolves a plain scalar to null, a boolean, a number or a
+// string, as the YAML core schema does
+func yamlScalar(s string) any {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n
+	}
+	if strings.ContainsAny(s, "0123456789") && !strings.ContainsAny(s, "xXpP_") {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return s
+}
+
+// stripComment removes a # comment outside quotes. In YAML (yaml set) a #
+// only starts a comment at the start of the line or after whitespace, and
+// quotes only open a scalar after a separator.
+func stripComment(line string, yaml bool) string {
+	quote := byte(0)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if !yaml || i == 0 || strings.IndexByte(" \t:[{,-", line[i-1]) >= 0 {
+				quote = c
+			}
+		case c == '#':
+			if !yaml || i == 0 || line[i-1] == ' ' || line[i-1] == '\t' {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
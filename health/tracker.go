@@ -0,0 +1,167 @@
+package health
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tracker records the outcome of data fetches of every symbol for liveness
+// and readiness checks
+type Tracker struct {
+	mu      sync.RWMutex
+	started time.Time
+	symbols map[string]*fetches
+}
+
+// fetches is the fetch history of one symbol
+type fetches struct {
+	lastAttempt time.Time
+	lastSuccess time.Time
+	lastErr     error
+}
+
+// Status is a snapshot of the tracker
+type Status struct {
+	// Symbol the status belongs to, empty before the first fetch
+	Symbol      string    `json:"symbol,omitempty"`
+	Started     time.Time `json:"started"`
+	LastAttempt time.Time `json:"last_attempt,omitzero"`
+	LastSuccess time.Time `json:"last_success,omitzero"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// NewTracker creates a tracker, the start time counts as the first activity
+func NewTracker() *Tracker {
+	return &Tracker{started: time.Now(), symbols: make(map[string]*fetches)}
+}
+
+// RecordFetch records a finished fetch attempt of symbol, err is nil on
+// success
+func (t *Tracker) RecordFetch(symbol string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	f, ok := t.symbols[symbol]
+	if !ok {
+		f = &fetches{}
+		t.symbols[symbol] = f
+	}
+	now := time.Now()
+	f.lastAttempt = now
+	f.lastErr = err
+	if err == nil {
+		f.lastSuccess = now
+	}
+}
+
+// Retain forgets the symbols not listed, e.g. ones a reload removed from
+// the watchlist
+func (t *Tracker) Retain(symbols ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	keep := make(map[string]bool, len(symbols))
+	for _, s := range symbols {
+		keep[s] = true
+	}
+	for s := range t.symbols {
+		if !keep[s] {
+			delete(t.symbols, s)
+		}
+	}
+}
+
+// Status returns the status of the worst symbol: a failing one before a
+// succeeding one, then the one with the oldest data. One healthy symbol
+// cannot hide another that is failing or stale.
+func (t *Tracker) Status() Status {
+	var worst Status
+	for i, s := range t.Symbols() {
+		if i == 0 || worse(s, worst) {
+			worst = s
+		}
+	}
+	if worst.Started.IsZero() {
+		worst.Started = t.started
+	}
+	return worst
+}
+
+// Symbol returns the status of one symbol
+func (t *Tracker) Symbol(symbol string) Status {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	s := Status{Symbol: symbol, Started: t.started}
+	if f, ok := t.symbols[symbol]; ok {
+		s = f.status(symbol, t.started)
+	}
+	return s
+}
+
+// Symbols returns the status of every symbol, ordered by symbol
+func (t *Tracker) Symbols() []Status {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	list := make([]Status, 0, len(t.symbols))
+	for symbol, f := range t.symbols {
+		list = append(list, f.status(symbol, t.started))
+	}
+	slices.SortFunc(list, func(a, b Status) int { return strings.Compare(a.Symbol, b.Symbol) })
+	return list
+}
+
+func (f *fetches) status(symbol string, started time.Time) Status {
+	s := Status{
+		Symbol:      symbol,
+		Started:     started,
+		LastAttempt: f.lastAttempt,
+		LastSuccess: f.lastSuccess,
+	}
+	if f.lastErr != nil {
+		s.LastError = f.lastErr.Error()
+	}
+	return s
+}
+
+// worse reports whether a is less healthy than b
+func worse(a, b Status) bool {
+	aFailing := a.LastError != "" || a.LastSuccess.IsZero()
+	bFailing := b.LastError != "" || b.LastSuccess.IsZero()
+	if aFailing != bFailing {
+		return aFailing
+	}
+	if !a.LastSuccess.Equal(b.LastSuccess) {
+		return a.LastSuccess.Before(b.LastSuccess)
+	}
+	return a.LastAttempt.Before(b.LastAttempt)
+}
+
+// Live reports whether the monitoring loop made progress within maxIdle.
+// A stuck loop stops recording attempts and should be restarted.
+func (s Status) Live(now time.Time, maxIdle time.Duration) bool {
+	last := s.LastAttempt
+	if last.IsZero() {
+		last = s.Started
+	}
+	return now.Sub(last) <= maxIdle
+}
+
+// Fresh reports whether the last fetch succeeded and its data is not older than maxStale
+func (s Status) Fresh(now time.Time, maxStale time.Duration) bool {
+	if s.LastSuccess.IsZero() || s.LastError != "" {
+		return false
+	}
+	return now.Sub(s.LastSuccess) <= maxStale
+}
+
+// Staleness returns the age of the last successful fetch, zero if there was none
+func (s Status) Staleness(now time.Time) time.Duration {
+	if s.LastSuccess.IsZero() {
+		return 0
+	}
+	return now.Sub(s.LastSuccess)
+}
@@ -0,0 +1,150 @@
+package i18n
+
+// en is the English message catalog, also the fallback for missing keys
+var en = map[string]string{
+	"app.log_init_failed": "❌ Failed to set up logging: %v",
+	"config.invalid":      "❌ Invalid configuration:",
+	"config.problem":      "   • %s",
+	"app.title":           "🚀 Gold Analyzer - starting automatic monitoring...",
+	"app.settings":        "⚙️  Settings:",
+	"app.symbol":          "   • Symbol: %s",
+	"app.interval":        "   • Interval: %s",
+	"app.range":           "   • Range: %s",
+	"app.check_interval":  "   • Check interval: %v",
+	"app.stop_hint":       "💡 Press Ctrl+C to stop...",
+
+	"notify.queue_load_failed": "⚠️  Failed to load notification queue: %v",
+	"notify.failed":            "⚠️  Failed to send notification: %v",
+
+	"alerts.load_failed":  "⚠️  Failed to load alerts: %v",
+	"alerts.loaded":       "🔔 Loaded %d alerts from %s",
+	"alerts.kept":         "   Keeping the %d alerts loaded before",
+	"alerts.fetch_failed": "❌ Failed to fetch data for alert (%s %s): %v",
+	"alerts.eval_failed":  "⚠️  Failed to evaluate alert: %v",
+	"alerts.header":       "\n🔔 Alerts:",
+	"alerts.item":         "   • %s",
+	"alerts.message":      "%s: %s(%s) = %.2f, %s %g",
+
+	"alerts.condition.above":       "above",
+	"alerts.condition.below":       "below",
+	"alerts.condition.cross_above": "crossed above",
+	"alerts.condition.cross_below": "crossed below",
+
+	"api.listening": "🌐 API listening on %s",
+	"api.failed":    "❌ API server error: %v",
+
+	"tui.title":        "🚀 Gold Analyzer — %s (%s)",
+	"tui.waiting":      "⏳ Waiting for the first analysis...",
+	"tui.price":        "💰 Price: %.2f USD",
+	"tui.rsi":          "RSI (%d): %.2f",
+	"tui.macd":         "MACD Histogram: %.6f",
+	"tui.atr":          "ATR (%d): %.2f",
+	"tui.signal":       "🎯 Signal: %s",
+	"tui.history":      "🕘 Recent signals:",
+	"tui.no_history":   "   (none yet)",
+	"tui.fetch_ok":     "🟢 Last fetch: %s",
+	"tui.fetch_failed": "🔴 Fetch failed at %s: %s",
+	"tui.footer":       "Ctrl+C to exit",
+
+	"report.title":       "Gold Analyzer report — %s",
+	"report.period":      "Interval %s, range %s: %s to %s",
+	"report.last_price":  "Last price",
+	"report.change":      "Change over period",
+	"report.range":       "Low – high",
+	"report.signals":     "Signals",
+	"report.last_signal": "Current signal",
+	"report.price_chart": "Price with BUY/SELL signals",
+	"report.rsi_chart":   "RSI (%d) with buy zone and sell threshold",
+	"report.macd_chart":  "MACD histogram",
+	"report.atr_chart":   "ATR (%d)",
+	"report.markers":     "Signal changes",
+	"report.time":        "Time",
+	"report.signal":      "Signal",
+	"report.price":       "Price",
+	"report.no_markers":  "No BUY or SELL signals in this period.",
+	"report.generated":   "Generated at %s",
+	"report.written":     "📄 Report written to %s",
+	"report.failed":      "❌ Report failed: %v",
+
+	"fetch.written": "💾 %d candles written to %s",
+	"fetch.failed":  "❌ Export failed: %v",
+
+	"backtest.header":       "📜 Backtest %s (%s, %s)",
+	"backtest.period":       "   %s → %s (%d bars)",
+	"backtest.trade":        "   • BUY %s @ %.2f → SELL %s @ %.2f: %+.2f%%",
+	"backtest.open_trade":   "   • BUY %s @ %.2f → open: %+.2f%%",
+	"backtest.trades":       "   Trades:        %d",
+	"backtest.total_return": "   Total return:  %+.2f%%",
+	"backtest.buy_hold":     "   Buy and hold:  %+.2f%%",
+	"backtest.win_rate":     "   Win rate:      %.1f%%",
+	"backtest.max_drawdown": "   Max drawdown:  %.2f%%",
+	"backtest.failed":       "❌ Backtest failed: %v",
+
+	"reload.applied":   "\n🔄 Configuration reloaded: %d change(s)",
+	"reload.unchanged": "\n🔄 Configuration reloaded: no changes",
+	"reload.change":    "   • %s: %s → %s",
+	"reload.restart":   "   ⚠️  %s: %s → %s (takes effect after a restart)",
+	"reload.failed":    "\n❌ Reload failed, keeping the current configuration",
+
+	"signal.received": "\n\n🛑 Received signal: %v",
+	"signal.stopping": "⏳ Stopping... (press Ctrl+C again to force exit)",
+
+	"analysis.checked_at":     "\n📊 Checked at: %s",
+	"analysis.symbol":         "🏷️  %s (%s)",
+	"analysis.fetch_failed":   "❌ Failed to fetch data: %v",
+	"analysis.no_data":        "⚠️  No data to analyze",
+	"analysis.failed":         "❌ Analysis failed: %v",
+	"analysis.price":          "\n💰 Current gold price: %.2f USD",
+	"analysis.change":         "   %s Change: %.2f USD (%.2f%%)",
+	"analysis.indicators":     "\n📈 Technical indicators:",
+	"analysis.rsi":            "   • RSI (%d):        %.2f",
+	"analysis.rsi_oversold":   " 🟦 Oversold",
+	"analysis.rsi_overbought": " 🟥 Overbought",
+	"analysis.rsi_buy_zone":   " 🟩 Buy zone",
+	"analysis.macd":           "   • MACD:            %.6f",
+	"analysis.macd_signal":    "   • MACD Signal:     %.6f",
+	"analysis.macd_hist":      "   • MACD Histogram:  %.6f",
+	"analysis.hist_positive":  " 📈 Positive",
+	"analysis.hist_negative":  " 📉 Negative",
+	"analysis.atr":            "   • ATR (%d):        %.2f",
+	"analysis.signal_header":  "\n🎯 Trading signal:",
+
+	"signal.buy":  "   ✅ Signal: BUY",
+	"signal.sell": "   ❌ Signal: SELL",
+	"signal.hold": "   ⏸️  Signal: HOLD",
+
+	"paper.opened": "   📂 Paper position opened @ %.2f",
+	"paper.closed": "   📁 Paper position closed @ %.2f: %+.2f%%",
+
+	"reason.buy_header":  "   Reasons for BUY:",
+	"reason.buy_rsi":     "      • RSI within buy zone (%.2f - %.2f)",
+	"reason.buy_macd":    "      • MACD histogram is positive",
+	"reason.buy_atr":     "      • ATR = %.2f (healthy volatility)",
+	"reason.sell_header": "   Reasons for SELL:",
+	"reason.sell_rsi":    "      • RSI is high (%.2f > %.2f) - overbought",
+	"reason.sell_macd":   "      • MACD histogram is negative - weakening trend",
+	"reason.hold_header": "   Reasons for HOLD:",
+	"reason.hold_market": "      • Market conditions do not favor an entry",
+	"reason.hold_values": "      • RSI = %.2f, MACD Hist = %.6f, ATR = %.2f",
+
+	"state.load_failed": "⚠️  Failed to load state: %v",
+	"state.restored":    "♻️  Restored the state of %d symbols and %d alerts saved at %s",
+	"state.pending":     "♻️  Restored %d undelivered notifications",
+	"state.saved":       "   ✓ State saved (%s)",
+	"stats.logs_saved":  "   ✓ Final logs saved (%s)",
+	"resources.closing": "\n🔐 Closing resources...",
+	"resources.closed":  "   ✓ All resources closed",
+
+	"shutdown.error":        "❌ Error during shutdown: %v",
+	"shutdown.start":        "🔄 Starting graceful shutdown...",
+	"shutdown.hook_error":   "⚠️  Shutdown hook %s failed: %v",
+	"shutdown.hook_timeout": "⏱️  Shutdown hook %s: %v",
+	"shutdown.waiting":      "⏳ Waiting for shutdown hooks: %s (%v)... press Ctrl+C again to force exit",
+	"shutdown.forced":       "⛔ %v received again, exiting without waiting for: %s",
+	"shutdown.stats":        "\n📊 Final stats:",
+	"shutdown.hooks_done":   "   ✓ All hooks completed",
+	"shutdown.hooks_failed": "   ✗ %d of %d hooks failed",
+	"shutdown.duration":     "   ✓ Shutdown duration: %v",
+	"shutdown.time":         "   ✓ Stopped at: %s",
+	"shutdown.done":         "✅ Application stopped successfully",
+}
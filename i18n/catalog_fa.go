@@ -0,0 +1,150 @@
+package i18n
+
+// fa is the Persian message catalog
+var fa = map[string]string{
+	"app.log_init_failed": "❌ خطا در راه‌اندازی لاگ: %v",
+	"config.invalid":      "❌ تنظیمات نامعتبر است:",
+	"config.problem":      "   • %s",
+	"app.title":           "🚀 Gold Analyzer - شروع نظارت خودکار...",
+	"app.settings":        "⚙️  تنظیمات:",
+	"app.symbol":          "   • نماد: %s",
+	"app.interval":        "   • بازه زمانی: %s",
+	"app.range":           "   • محدوده: %s",
+	"app.check_interval":  "   • فاصله بررسی: %v",
+	"app.stop_hint":       "💡 برای متوقف کردن، Ctrl+C را فشار دهید...",
+
+	"notify.queue_load_failed": "⚠️  خطا در بارگذاری صف اعلان‌ها: %v",
+	"notify.failed":            "⚠️  خطا در ارسال اعلان: %v",
+
+	"alerts.load_failed":  "⚠️  خطا در بارگذاری هشدارها: %v",
+	"alerts.loaded":       "🔔 %d هشدار از %s بارگذاری شد",
+	"alerts.kept":         "   %d هشدار قبلی همچنان فعال می‌مانند",
+	"alerts.fetch_failed": "❌ خطا در دریافت داده برای هشدار (%s %s): %v",
+	"alerts.eval_failed":  "⚠️  خطا در بررسی هشدار: %v",
+	"alerts.header":       "\n🔔 هشدارها:",
+	"alerts.item":         "   • %s",
+	"alerts.message":      "%s: %s(%s) = %.2f، %s %g",
+
+	"alerts.condition.above":       "بالاتر از",
+	"alerts.condition.below":       "پایین‌تر از",
+	"alerts.condition.cross_above": "عبور به بالای",
+	"alerts.condition.cross_below": "عبور به زیر",
+
+	"api.listening": "🌐 API در حال اجرا روی %s",
+	"api.failed":    "❌ خطا در اجرای API: %v",
+
+	"tui.title":        "🚀 Gold Analyzer — %s (%s)",
+	"tui.waiting":      "⏳ در انتظار اولین بررسی...",
+	"tui.price":        "💰 قیمت: %.2f USD",
+	"tui.rsi":          "RSI (%d): %.2f",
+	"tui.macd":         "هیستوگرام MACD: %.6f",
+	"tui.atr":          "ATR (%d): %.2f",
+	"tui.signal":       "🎯 سیگنال: %s",
+	"tui.history":      "🕘 سیگنال‌های اخیر:",
+	"tui.no_history":   "   (هنوز موردی نیست)",
+	"tui.fetch_ok":     "🟢 آخرین دریافت: %s",
+	"tui.fetch_failed": "🔴 خطا در دریافت در %s: %s",
+	"tui.footer":       "برای خروج Ctrl+C را فشار دهید",
+
+	"report.title":       "گزارش Gold Analyzer — %s",
+	"report.period":      "بازه %s، محدوده %s: از %s تا %s",
+	"report.last_price":  "آخرین قیمت",
+	"report.change":      "تغییر در دوره",
+	"report.range":       "کمترین – بیشترین",
+	"report.signals":     "سیگنال‌ها",
+	"report.last_signal": "سیگنال فعلی",
+	"report.price_chart": "قیمت با سیگنال‌های خرید/فروش",
+	"report.rsi_chart":   "RSI (%d) با محدوده خرید و آستانه فروش",
+	"report.macd_chart":  "هیستوگرام MACD",
+	"report.atr_chart":   "ATR (%d)",
+	"report.markers":     "تغییرات سیگنال",
+	"report.time":        "زمان",
+	"report.signal":      "سیگنال",
+	"report.price":       "قیمت",
+	"report.no_markers":  "در این دوره سیگنال خرید یا فروشی وجود ندارد.",
+	"report.generated":   "تولید شده در %s",
+	"report.written":     "📄 گزارش در %s ذخیره شد",
+	"report.failed":      "❌ خطا در تولید گزارش: %v",
+
+	"fetch.written": "💾 %d شمع در %s ذخیره شد",
+	"fetch.failed":  "❌ خطا در خروجی گرفتن: %v",
+
+	"backtest.header":       "📜 بک‌تست %s (%s، %s)",
+	"backtest.period":       "   %s ← %s (%d شمع)",
+	"backtest.trade":        "   • خرید %s @ %.2f ← فروش %s @ %.2f: %+.2f%%",
+	"backtest.open_trade":   "   • خرید %s @ %.2f ← باز: %+.2f%%",
+	"backtest.trades":       "   تعداد معاملات:   %d",
+	"backtest.total_return": "   بازده کل:        %+.2f%%",
+	"backtest.buy_hold":     "   خرید و نگهداری:  %+.2f%%",
+	"backtest.win_rate":     "   نرخ برد:         %.1f%%",
+	"backtest.max_drawdown": "   بیشترین افت:     %.2f%%",
+	"backtest.failed":       "❌ خطا در بک‌تست: %v",
+
+	"reload.applied":   "\n🔄 تنظیمات دوباره بارگذاری شد: %d تغییر",
+	"reload.unchanged": "\n🔄 تنظیمات دوباره بارگذاری شد: بدون تغییر",
+	"reload.change":    "   • %s: %s → %s",
+	"reload.restart":   "   ⚠️  %s: %s → %s (پس از راه‌اندازی مجدد اعمال می‌شود)",
+	"reload.failed":    "\n❌ بارگذاری مجدد ناموفق بود، تنظیمات فعلی حفظ شد",
+
+	"signal.received": "\n\n🛑 سیگنال دریافت شد: %v",
+	"signal.stopping": "⏳ درحال متوقف کردن برنامه... (برای خروج فوری دوباره Ctrl+C بزنید)",
+
+	"analysis.checked_at":     "\n📊 بررسی در: %s",
+	"analysis.symbol":         "🏷️  %s (%s)",
+	"analysis.fetch_failed":   "❌ خطا در دریافت داده: %v",
+	"analysis.no_data":        "⚠️  داده‌ای برای تجزیه و تحلیل وجود ندارد",
+	"analysis.failed":         "❌ خطا در تحلیل: %v",
+	"analysis.price":          "\n💰 قیمت فعلی طلا: %.2f USD",
+	"analysis.change":         "   %s تغییر: %.2f USD (%.2f%%)",
+	"analysis.indicators":     "\n📈 اندیکاتورهای تکنیکال:",
+	"analysis.rsi":            "   • RSI (%d):        %.2f",
+	"analysis.rsi_oversold":   " 🟦 فروش زیادی",
+	"analysis.rsi_overbought": " 🟥 خرید زیادی",
+	"analysis.rsi_buy_zone":   " 🟩 محدوده مناسب",
+	"analysis.macd":           "   • MACD:            %.6f",
+	"analysis.macd_signal":    "   • MACD Signal:     %.6f",
+	"analysis.macd_hist":      "   • MACD Histogram:  %.6f",
+	"analysis.hist_positive":  " 📈 مثبت",
+	"analysis.hist_negative":  " 📉 منفی",
+	"analysis.atr":            "   • ATR (%d):        %.2f",
+	"analysis.signal_header":  "\n🎯 سیگنال معاملاتی:",
+
+	"signal.buy":  "   ✅ سیگنال: خریدش کن (BUY)",
+	"signal.sell": "   ❌ سیگنال: بفروش (SELL)",
+	"signal.hold": "   ⏸️  سیگنال: نگاه کن (HOLD)",
+
+	"paper.opened": "   📂 پوزیشن آزمایشی باز شد @ %.2f",
+	"paper.closed": "   📁 پوزیشن آزمایشی بسته شد @ %.2f: %+.2f%%",
+
+	"reason.buy_header":  "   دلایل سیگنال خرید:",
+	"reason.buy_rsi":     "      • RSI در محدوده مناسب (%.2f - %.2f)",
+	"reason.buy_macd":    "      • MACD Histogram مثبت است",
+	"reason.buy_atr":     "      • ATR = %.2f (نوسان خوب)",
+	"reason.sell_header": "   دلایل سیگنال فروش:",
+	"reason.sell_rsi":    "      • RSI بالا است (%.2f > %.2f) - اشباع خریدار",
+	"reason.sell_macd":   "      • MACD Histogram منفی است - تضعیف روند",
+	"reason.hold_header": "   دلایل سیگنال انتظار:",
+	"reason.hold_market": "      • شرایط بازار مناسب برای ورود نیست",
+	"reason.hold_values": "      • RSI = %.2f, MACD Hist = %.6f, ATR = %.2f",
+
+	"state.load_failed": "⚠️  خطا در بارگذاری وضعیت: %v",
+	"state.restored":    "♻️  وضعیت %d نماد و %d هشدار ذخیره‌شده در %s بازیابی شد",
+	"state.pending":     "♻️  %d اعلان ارسال‌نشده بازیابی شد",
+	"state.saved":       "   ✓ وضعیت ذخیره شد (%s)",
+	"stats.logs_saved":  "   ✓ لاگ‌های نهایی ذخیره شدند (%s)",
+	"resources.closing": "\n🔐 بستن منابع...",
+	"resources.closed":  "   ✓ تمام منابع بسته شدند",
+
+	"shutdown.error":        "❌ خطا در طول Shutdown: %v",
+	"shutdown.start":        "🔄 شروع خاتمهٔ نرم برنامه...",
+	"shutdown.hook_error":   "⚠️  خطا در shutdown hook %s: %v",
+	"shutdown.hook_timeout": "⏱️  مهلت shutdown hook %s تمام شد: %v",
+	"shutdown.waiting":      "⏳ در انتظار shutdown hookها: %s (%v)... برای خروج فوری دوباره Ctrl+C بزنید",
+	"shutdown.forced":       "⛔ %v دوباره دریافت شد، خروج بدون انتظار برای: %s",
+	"shutdown.stats":        "\n📊 آمار نهایی:",
+	"shutdown.hooks_done":   "   ✓ تمام hooks تکمیل شدند",
+	"shutdown.hooks_failed": "   ✗ %d از %d hook ناموفق بودند",
+	"shutdown.duration":     "   ✓ مدت زمان Shutdown: %v",
+	"shutdown.time":         "   ✓ زمان خاتمه: %s",
+	"shutdown.done":         "✅ برنامه با موفقیت متوقف شد",
+}
@@ -0,0 +1,163 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Supported languages
+const (
+	FA = "fa"
+	EN = "en"
+)
+
+// DefaultLang is used when neither the configuration nor LANG select a supported language
+const DefaultLang = FA
+
+// Bidi control characters used to keep left-to-right values (numbers,
+// symbols, English terms) intact inside right-to-left text.
+const (
+	lri = "\u2066" // LEFT-TO-RIGHT ISOLATE
+	pdi = "\u2069" // POP DIRECTIONAL ISOLATE
+)
+
+var catalogs = map[string]map[string]string{
+	FA: fa,
+	EN: en,
+}
+
+var rtlLangs = map[string]bool{FA: true}
+
+// verbPattern matches fmt verbs, %% is handled separately
+var verbPattern = regexp.MustCompile(`%[-+# 0]*(?:\d+|\*)?(?:\.(?:\d+|\*))?[a-zA-Z]`)
+
+// Printer renders catalog messages in one language
+type Printer struct {
+	lang     string
+	messages map[string]string
+	// Isolate wraps formatted values in bidi isolates for RTL languages
+	Isolate bool
+	// Calendar used by Time: Gregorian (default), Jalali or Both
+	Calendar string
+	// Location used by Time; nil keeps the timestamp's own zone
+	Location *time.Location
+}
+
+// New creates a printer for lang, falling back to DefaultLang if unsupported
+func New(lang string) *Printer {
+	lang = normalize(lang)
+	if _, ok := catalogs[lang]; !ok {
+		lang = DefaultLang
+	}
+	return &Printer{
+		lang:     lang,
+		messages: catalogs[lang],
+		Isolate:  rtlLangs[lang],
+	}
+}
+
+// Resolve picks the language from the configured value, then LC_ALL,
+// LC_MESSAGES and LANG, and finally DefaultLang.
+func Resolve(configured string) string {
+	candidates := []string{configured, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")}
+	for _, c := range candidates {
+		if lang := normalize(c); catalogs[lang] != nil {
+			return lang
+		}
+	}
+	return DefaultLang
+}
+
+// Supported reports whether a language or locale name like fa_IR.UTF-8
+// has a catalog
+func Supported(lang string) bool {
+	return catalogs[normalize(lang)] != nil
+}
+
+// normalize turns locale names like fa_IR.UTF-8 into a language code
+func normalize(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "_-.@"); i >= 0 {
+		locale = locale[:i]
+	}
+	return locale
+}
+
+// Lang returns the language code of the printer
+func (p *Printer) Lang() string {
+	return p.lang
+}
+
+// RTL reports whether the language is written right to left
+func (p *Printer) RTL() bool {
+	return rtlLangs[p.lang]
+}
+
+// T formats the message for key. Unknown keys fall back to English and
+// then to the key itself so a missing translation is visible but harmless.
+func (p *Printer) T(key string, args ...any) string {
+	msg, ok := p.messages[key]
+	if !ok {
+		if msg, ok = en[key]; !ok {
+			msg = key
+		}
+	}
+
+	if len(args) == 0 {
+		return msg
+	}
+	if p.Isolate {
+		msg = isolateVerbs(msg)
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// isolateVerbs wraps every formatting verb in an LTR isolate
+func isolateVerbs(format string) string {
+	parts := strings.Split(format, "%%")
+	for i, part := range parts {
+		parts[i] = verbPattern.ReplaceAllStringFunc(part, func(verb string) string {
+			return lri + verb + pdi
+		})
+	}
+	return strings.Join(parts, "%%")
+}
+
+var (
+	defaultMu      sync.RWMutex
+	defaultPrinter = New(DefaultLang)
+)
+
+// Default returns the printer used by the package level T
+func Default() *Printer {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultPrinter
+}
+
+// SetDefault replaces the printer used by the package level T
+func SetDefault(p *Printer) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultPrinter = p
+}
+
+// T formats a message with the default printer
+func T(key string, args ...any) string {
+	return Default().T(key, args...)
+}
+
+// Keys returns the message keys of a language catalog, sorted
+func Keys(lang string) []string {
+	keys := make([]string, 0, len(catalogs[lang]))
+	for k := range catalogs[lang] {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,72 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gold-analyzer/jalali"
+)
+
+// Calendars for displayed timestamps
+const (
+	Gregorian = "gregorian"
+	Jalali    = "jalali"
+	Both      = "both"
+)
+
+// TehranZone is the default location for Jalali timestamps
+const TehranZone = "Asia/Tehran"
+
+const gregorianLayout = "2006-01-02 15:04:05"
+
+// ParseCalendar validates a TIME_CALENDAR value
+func ParseCalendar(s string) (string, error) {
+	switch c := strings.ToLower(strings.TrimSpace(s)); c {
+	case "":
+		return Gregorian, nil
+	case Gregorian, Jalali, Both:
+		return c, nil
+	}
+	return "", fmt.Errorf("unknown calendar %q (use gregorian, jalali or both)", s)
+}
+
+// LoadLocation resolves the display timezone. An empty name keeps the local
+// zone for Gregorian output and uses Tehran for Jalali. Tehran falls back to
+// its fixed +03:30 offset when tzdata is not installed.
+func LoadLocation(name, calendar string) (*time.Location, error) {
+	if name == "" {
+		if calendar == Gregorian || calendar == "" {
+			return time.Local, nil
+		}
+		name = TehranZone
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil && name == TehranZone {
+		// Iran has not observed daylight saving time since 2022
+		return time.FixedZone("+0330", 3*3600+30*60), nil
+	}
+	return loc, err
+}
+
+// Time formats a timestamp in the printer's calendar and location
+func (p *Printer) Time(t time.Time) string {
+	if p.Location != nil {
+		t = t.In(p.Location)
+	}
+
+	switch p.Calendar {
+	case Jalali:
+		return jalali.Format(t)
+	case Both:
+		return jalali.Format(t) + " (" + t.Format(gregorianLayout) + ")"
+	default:
+		return t.Format(gregorianLayout)
+	}
+}
+
+// Time formats a timestamp with the default printer
+func Time(t time.Time) string {
+	return Default().Time(t)
+}
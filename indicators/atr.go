@@ -1,8 +1,32 @@
 package indicators
 
-func ATR(high, low, close []float64, period int) []float64 {
+import (
+	"fmt"
+	"math"
+)
+
+// ATRValidFrom is the index of the first ATR value
+func ATRValidFrom(period int) int {
+	return period
+}
+
+// ATR returns Wilder's average true range. Values before
+// ATRValidFrom(period) are NaN. It needs more than period bars and equally
+// long high, low and close slices.
+func ATR(high, low, close []float64, period int) ([]float64, error) {
+	if len(high) != len(close) || len(low) != len(close) {
+		return nil, fmt.Errorf("ATR(%d): %w: high %d, low %d, close %d",
+			period, ErrLengthMismatch, len(high), len(low), len(close))
+	}
+	if err := check("ATR", period, len(close), ATRValidFrom(period)); err != nil {
+		return nil, err
+	}
+
 	atr := make([]float64, len(close))
 	tr := make([]float64, len(close))
+	for i := 0; i < period; i++ {
+		atr[i] = math.NaN()
+	}
 
 	for i := 1; i < len(close); i++ {
 		hL := high[i] - low[i]
@@ -20,7 +44,7 @@ func ATR(high, low, close []float64, period int) []float64 {
 	for i := period + 1; i < len(tr); i++ {
 		atr[i] = (atr[i-1]*float64(period-1) + tr[i]) / float64(period)
 	}
-	return atr
+	return atr, nil
 }
 
 func abs(x float64) float64 {
@@ -35,3 +59,48 @@ func max(a, b float64) float64 {
 	}
 	return b
 }
+
+// ATRStream computes ATR one bar at a time, with the same values as ATR on
+// the bars so far. Like RSIStream, a copy is independent of the original.
+type ATRStream struct {
+	period    int
+	bars      int
+	prevClose float64
+	value     float64
+}
+
+// NewATRStream creates an ATR stream
+func NewATRStream(period int) (*ATRStream, error) {
+	if err := checkPeriod("ATR", period); err != nil {
+		return nil, err
+	}
+	return &ATRStream{period: period}, nil
+}
+
+// Update adds the next bar and returns the ATR on it, NaN while warming up
+func (s *ATRStream) Update(high, low, close float64) float64 {
+	i := s.bars
+	s.bars++
+	prev := s.prevClose
+	s.prevClose = close
+	if i == 0 {
+		return math.NaN()
+	}
+
+	hL := high - low
+	hC := abs(high - prev)
+	lC := abs(low - prev)
+	tr := max(hL, max(hC, lC))
+
+	// value holds the sum of the true ranges until the first average
+	switch {
+	case i < s.period:
+		s.value += tr
+		return math.NaN()
+	case i == s.period:
+		s.value = (s.value + tr) / float64(s.period)
+	default:
+		s.value = (s.value*float64(s.period-1) + tr) / float64(s.period)
+	}
+	return s.value
+}
@@ -1,29 +1,164 @@
 package indicators
 
-func EMA(data []float64, period int) []float64 {
+import (
+	"fmt"
+	"math"
+)
+
+// EMAValidFrom is the index of the first EMA value
+func EMAValidFrom(period int) int {
+	return period - 1
+}
+
+// EMA returns the exponential moving average of data, seeded with the
+// simple average of the first period values. Values before the seed are
+// NaN; leading NaN values in data (e.g. the MACD line) delay the seed. It
+// needs at least period values after them.
+func EMA(data []float64, period int) ([]float64, error) {
+	start := 0
+	for start < len(data) && math.IsNaN(data[start]) {
+		start++
+	}
+	if err := check("EMA", period, len(data)-start, EMAValidFrom(period)); err != nil {
+		return nil, err
+	}
+
 	ema := make([]float64, len(data))
-	k := 2.0 / float64(period+1)
-	ema[0] = data[0]
+	seed := start + EMAValidFrom(period)
+	for i := 0; i < seed; i++ {
+		ema[i] = math.NaN()
+	}
+
+	var sum float64
+	for _, v := range data[start : seed+1] {
+		sum += v
+	}
+	ema[seed] = sum / float64(period)
 
-	for i := 1; i < len(data); i++ {
+	k := 2.0 / float64(period+1)
+	for i := seed + 1; i < len(data); i++ {
 		ema[i] = data[i]*k + ema[i-1]*(1-k)
 	}
-	return ema
+	return ema, nil
+}
+
+// Default MACD periods
+const (
+	MACDFast   = 8
+	MACDSlow   = 21
+	MACDSignal = 5
+)
+
+func MACD(closes []float64) (macd, signal, hist []float64, err error) {
+	return MACDPeriods(closes, MACDFast, MACDSlow, MACDSignal)
+}
+
+// MACDValidFrom is the index of the first MACD signal and histogram value;
+// the MACD line itself starts at EMAValidFrom of the slower period
+func MACDValidFrom(fastPeriod, slowPeriod, signalPeriod int) int {
+	slower := slowPeriod
+	if fastPeriod > slower {
+		slower = fastPeriod
+	}
+	return EMAValidFrom(slower) + EMAValidFrom(signalPeriod)
 }
 
-func MACD(closes []float64) (macd, signal, hist []float64) {
-	fast := EMA(closes, 8)
-	slow := EMA(closes, 21)
+// MACDPeriods computes MACD with custom EMA periods. Values before
+// MACDValidFrom are NaN. It needs more than MACDValidFrom closes.
+func MACDPeriods(closes []float64, fastPeriod, slowPeriod, signalPeriod int) (macd, signal, hist []float64, err error) {
+	fast, err := EMA(closes, fastPeriod)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("MACD fast: %w", err)
+	}
+	slow, err := EMA(closes, slowPeriod)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("MACD slow: %w", err)
+	}
 
 	macd = make([]float64, len(closes))
 	for i := range closes {
 		macd[i] = fast[i] - slow[i]
 	}
 
-	signal = EMA(macd, 5)
+	signal, err = EMA(macd, signalPeriod)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("MACD signal: %w", err)
+	}
 	hist = make([]float64, len(macd))
 	for i := range macd {
 		hist[i] = macd[i] - signal[i]
 	}
-	return
+	return macd, signal, hist, nil
+}
+
+// EMAStream computes EMA one value at a time, with the same values as EMA
+// on the values so far. Like RSIStream, a copy is independent of the
+// original.
+type EMAStream struct {
+	period int
+	// count is the number of values since the leading NaN values
+	count int
+	k     float64
+	value float64
+}
+
+// NewEMAStream creates an EMA stream
+func NewEMAStream(period int) (*EMAStream, error) {
+	if err := checkPeriod("EMA", period); err != nil {
+		return nil, err
+	}
+	return &EMAStream{period: period, k: 2.0 / float64(period+1)}, nil
+}
+
+// Update adds the next value and returns the EMA on it, NaN while warming
+// up
+func (s *EMAStream) Update(v float64) float64 {
+	if s.count == 0 && math.IsNaN(v) {
+		return math.NaN()
+	}
+	s.count++
+
+	// value holds the sum of the values until the seed
+	switch {
+	case s.count < s.period:
+		s.value += v
+		return math.NaN()
+	case s.count == s.period:
+		s.value = (s.value + v) / float64(s.period)
+	default:
+		s.value = v*s.k + s.value*(1-s.k)
+	}
+	return s.value
+}
+
+// MACDStream computes MACD one close at a time, with the same values as
+// MACDPeriods on the closes so far. Like RSIStream, a copy is independent
+// of the original.
+type MACDStream struct {
+	fast, slow, signal EMAStream
+}
+
+// NewMACDStream creates a MACD stream with custom EMA periods
+func NewMACDStream(fastPeriod, slowPeriod, signalPeriod int) (*MACDStream, error) {
+	fast, err := NewEMAStream(fastPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("MACD fast: %w", err)
+	}
+	slow, err := NewEMAStream(slowPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("MACD slow: %w", err)
+	}
+	signal, err := NewEMAStream(signalPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("MACD signal: %w", err)
+	}
+	return &MACDStream{fast: *fast, slow: *slow, signal: *signal}, nil
+}
+
+// Update adds the next close and returns the MACD values on it, NaN while
+// warming up
+func (s *MACDStream) Update(close float64) (macd, signal, hist float64) {
+	macd = s.fast.Update(close) - s.slow.Update(close)
+	signal = s.signal.Update(macd)
+	return macd, signal, macd - signal
 }
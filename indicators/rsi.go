@@ -1,7 +1,23 @@
 package indicators
 
-func RSI(closes []float64, period int) []float64 {
+import "math"
+
+// RSIValidFrom is the index of the first RSI value
+func RSIValidFrom(period int) int {
+	return period
+}
+
+// RSI returns Wilder's relative strength index. Values before
+// RSIValidFrom(period) are NaN. It needs more than period closes.
+func RSI(closes []float64, period int) ([]float64, error) {
+	if err := check("RSI", period, len(closes), RSIValidFrom(period)); err != nil {
+		return nil, err
+	}
+
 	rsi := make([]float64, len(closes))
+	for i := 0; i < period; i++ {
+		rsi[i] = math.NaN()
+	}
 
 	var gain, loss float64
 	for i := 1; i <= period; i++ {
@@ -15,6 +31,7 @@ func RSI(closes []float64, period int) []float64 {
 
 	avgGain := gain / float64(period)
 	avgLoss := loss / float64(period)
+	rsi[period] = rsiValue(avgGain, avgLoss)
 
 	for i := period + 1; i < len(closes); i++ {
 		diff := closes[i] - closes[i-1]
@@ -26,12 +43,68 @@ func RSI(closes []float64, period int) []float64 {
 		}
 		avgGain = (avgGain*float64(period-1) + g) / float64(period)
 		avgLoss = (avgLoss*float64(period-1) + l) / float64(period)
+		rsi[i] = rsiValue(avgGain, avgLoss)
+	}
+	return rsi, nil
+}
 
-		if avgLoss == 0 {
-			rsi[i] = 100
-		} else {
-			rsi[i] = 100 - (100 / (1 + avgGain/avgLoss))
-		}
+func rsiValue(avgGain, avgLoss float64) float64 {
+	if avgLoss == 0 {
+		return 100
+	}
+	return 100 - (100 / (1 + avgGain/avgLoss))
+}
+
+// RSIStream computes RSI one close at a time, with the same values as RSI
+// on the closes so far. A copy is independent of the original, so the
+// unfinished candle can be evaluated on a copy without advancing the
+// stream.
+type RSIStream struct {
+	period           int
+	bars             int
+	prev             float64
+	avgGain, avgLoss float64
+	value            float64
+}
+
+// NewRSIStream creates an RSI stream
+func NewRSIStream(period int) (*RSIStream, error) {
+	if err := checkPeriod("RSI", period); err != nil {
+		return nil, err
+	}
+	return &RSIStream{period: period, value: math.NaN()}, nil
+}
+
+// Update adds the next close and returns the RSI on it, NaN while warming
+// up
+func (s *RSIStream) Update(close float64) float64 {
+	i := s.bars
+	s.bars++
+	diff := close - s.prev
+	s.prev = close
+	if i == 0 {
+		return s.value
+	}
+
+	var g, l float64
+	if diff > 0 {
+		g = diff
+	} else {
+		l = -diff
+	}
+
+	switch {
+	case i < s.period:
+		s.avgGain += g
+		s.avgLoss += l
+	case i == s.period:
+		s.avgGain = (s.avgGain + g) / float64(s.period)
+		s.avgLoss = (s.avgLoss + l) / float64(s.period)
+		s.value = rsiValue(s.avgGain, s.avgLoss)
+	default:
+		s.avgGain = (s.avgGain*float64(s.period-1) + g) / float64(s.period)
+		s.avgLoss = (s.avgLoss*float64(s.period-1) + l) / float64(s.period)
+		s.value = rsiValue(s.avgGain, s.avgLoss)
 	}
-	return rsi
+	return s.value
 }
@@ -0,0 +1,34 @@
+package indicators
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors for invalid indicator input, wrapped with the indicator and the
+// offending values
+var (
+	ErrPeriod         = errors.New("period must be positive")
+	ErrTooShort       = errors.New("not enough values")
+	ErrLengthMismatch = errors.New("input lengths differ")
+)
+
+// check validates the period and that n values reach the first valid
+// index from
+func check(name string, period, n, from int) error {
+	if err := checkPeriod(name, period); err != nil {
+		return err
+	}
+	if n <= from {
+		return fmt.Errorf("%s(%d): %w: need %d, got %d", name, period, ErrTooShort, from+1, n)
+	}
+	return nil
+}
+
+// checkPeriod validates the period alone
+func checkPeriod(name string, period int) error {
+	if period < 1 {
+		return fmt.Errorf("%s(%d): %w", name, period, ErrPeriod)
+	}
+	return nil
+}
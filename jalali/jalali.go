@@ -0,0 +1,151 @@
+// Package jalali converts between the Gregorian and the Jalali (Solar Hijri)
+// calendars. The arithmetic follows the well known jalaali algorithm by
+// Kazimierz M. Borkowski, valid for Jalali years -61 to 3177.
+package jalali
+
+import (
+	"fmt"
+	"time"
+)
+
+// MonthNames are the Persian names of the Jalali months
+var MonthNames = [12]string{
+	"فروردین", "اردیبهشت", "خرداد", "تیر", "مرداد", "شهریور",
+	"مهر", "آبان", "آذر", "دی", "بهمن", "اسفند",
+}
+
+// breaks are the Jalali years starting a new 33/29/37 year leap cycle
+var breaks = []int{
+	-61, 9, 38, 199, 426, 686, 756, 818, 1111, 1181, 1210,
+	1635, 2060, 2097, 2192, 2262, 2324, 2394, 2456, 3178,
+}
+
+// Date is a day in the Jalali calendar
+type Date struct {
+	Year  int
+	Month int
+	Day   int
+}
+
+// String formats the date as YYYY/MM/DD
+func (d Date) String() string {
+	return fmt.Sprintf("%04d/%02d/%02d", d.Year, d.Month, d.Day)
+}
+
+// MonthName returns the Persian name of the month
+func (d Date) MonthName() string {
+	if d.Month < 1 || d.Month > 12 {
+		return ""
+	}
+	return MonthNames[d.Month-1]
+}
+
+// FromGregorian converts a Gregorian date to Jalali
+func FromGregorian(year int, month time.Month, day int) Date {
+	return fromJDN(gregorianToJDN(year, int(month), day))
+}
+
+// FromTime converts the calendar day of t, in t's location, to Jalali
+func FromTime(t time.Time) Date {
+	y, m, d := t.Date()
+	return FromGregorian(y, m, d)
+}
+
+// ToGregorian converts a Jalali date to Gregorian
+func ToGregorian(d Date) (year int, month time.Month, day int) {
+	y, m, dd := jdnToGregorian(d.jdn())
+	return y, time.Month(m), dd
+}
+
+// IsLeap reports whether the Jalali year has 366 days
+func IsLeap(year int) bool {
+	leap, _, _ := cal(year)
+	return leap == 0
+}
+
+// Format renders t as "YYYY/MM/DD HH:MM:SS" in the Jalali calendar
+func Format(t time.Time) string {
+	return fmt.Sprintf("%s %02d:%02d:%02d", FromTime(t), t.Hour(), t.Minute(), t.Second())
+}
+
+// cal returns the leap state of the Jalali year (0 = leap), the matching
+// Gregorian year and the March day on which the Jalali year starts.
+func cal(jy int) (leap, gy, march int) {
+	gy = jy + 621
+	leapJ := -14
+	jp := breaks[0]
+	jump := 0
+
+	for i := 1; i < len(breaks); i++ {
+		jm := breaks[i]
+		jump = jm - jp
+		if jy < jm {
+			break
+		}
+		leapJ += jump/33*8 + (jump%33)/4
+		jp = jm
+	}
+
+	n := jy - jp
+	leapJ += n/33*8 + (n%33+3)/4
+	if jump%33 == 4 && jump-n == 4 {
+		leapJ++
+	}
+
+	leapG := gy/4 - (gy/100+1)*3/4 - 150
+	march = 20 + leapJ - leapG
+
+	if jump-n < 6 {
+		n = n - jump + (jump+4)/33*33
+	}
+	leap = ((n+1)%33 - 1) % 4
+	if leap == -1 {
+		leap = 4
+	}
+	return leap, gy, march
+}
+
+// jdn returns the Julian Day Number of a Jalali date
+func (d Date) jdn() int {
+	_, gy, march := cal(d.Year)
+	return gregorianToJDN(gy, 3, march) + (d.Month-1)*31 - d.Month/7*(d.Month-7) + d.Day - 1
+}
+
+// fromJDN converts a Julian Day Number to a Jalali date
+func fromJDN(jdn int) Date {
+	gy, _, _ := jdnToGregorian(jdn)
+	jy := gy - 621
+	leap, _, march := cal(jy)
+	k := jdn - gregorianToJDN(gy, 3, march)
+
+	if k >= 0 {
+		if k <= 185 {
+			// First six months have 31 days
+			return Date{Year: jy, Month: 1 + k/31, Day: k%31 + 1}
+		}
+		k -= 186
+	} else {
+		// Date falls in the previous Jalali year
+		jy--
+		k += 179
+		if leap == 1 {
+			k++
+		}
+	}
+	return Date{Year: jy, Month: 7 + k/30, Day: k%30 + 1}
+}
+
+func gregorianToJDN(gy, gm, gd int) int {
+	d := (gy+(gm-8)/6+100100)*1461/4 + (153*((gm+9)%12)+2)/5 + gd - 34840408
+	return d - (gy+100100+(gm-8)/6)/100*3/4 + 752
+}
+
+func jdnToGregorian(jdn int) (gy, gm, gd int) {
+	j := 4*jdn + 139361631
+	j += (4*jdn+183187720)/146097*3/4*4 - 3908
+	i := (j%1461)/4*5 + 308
+	gd = (i%153)/5 + 1
+	gm = (i/153)%12 + 1
+	gy = j/1461 - 100100 + (8-gm)/6
+	return gy, gm, gd
+}
@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"log/slog"
+	"time"
+
+	"gold-analyzer/i18n"
+	"gold-analyzer/jalali"
+)
+
+// jalaliKey holds the Jalali timestamp when both calendars are logged
+const jalaliKey = "time_jalali"
+
+// rfc3339Millis is the layout of the built-in handlers' time field
+const rfc3339Millis = "2006-01-02T15:04:05.000Z07:00"
+
+// replaceTime renders record timestamps in the Jalali calendar. With
+// i18n.Jalali the standard time field is replaced, with i18n.Both the
+// Gregorian time is kept and a time_jalali attribute follows it. Both stay
+// at the start of the record, outside any group.
+func replaceTime(calendar string, location *time.Location) func([]string, slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) > 0 || a.Key != slog.TimeKey || a.Value.Kind() != slog.KindTime {
+			return a
+		}
+
+		t := a.Value.Time().In(location)
+		if calendar == i18n.Jalali {
+			return slog.String(slog.TimeKey, jalali.Format(t))
+		}
+		// A group without a key is inlined, so one attribute becomes two
+		return slog.Attr{Value: slog.GroupValue(
+			slog.String(slog.TimeKey, a.Value.Time().Format(rfc3339Millis)),
+			slog.String(jalaliKey, jalali.Format(t)),
+		)}
+	}
+}
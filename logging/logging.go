@@ -0,0 +1,106 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"gold-analyzer/config"
+	"gold-analyzer/i18n"
+)
+
+// Output is the shared destination of all log records
+type Output interface {
+	io.Writer
+	// Sync flushes buffered data to stable storage
+	Sync() error
+	// Close flushes and releases the destination
+	Close() error
+}
+
+// New creates the application logger from the configuration. All records
+// go through one shared writer that is opened once; close it on shutdown.
+//
+// LOG_FILE selects the destination: empty disables logging, "stdout" and
+// "stderr" write to the console and anything else is a rotated file.
+func New(cfg *config.Config) (*slog.Logger, Output, error) {
+	level, err := ParseLevel(cfg.LogLevel)
+	if err != nil {
+		return nil, nil, err
+	}
+	calendar, err := i18n.ParseCalendar(cfg.TimeCalendar)
+	if err != nil {
+		return nil, nil, err
+	}
+	location, err := i18n.LoadLocation(cfg.TimeZone, calendar)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	w, err := openOutput(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	if calendar != i18n.Gregorian {
+		opts.ReplaceAttr = replaceTime(calendar, location)
+	}
+	var handler slog.Handler
+	switch strings.ToLower(cfg.LogFormat) {
+	case "json":
+		handler = slog.NewJSONHandler(w, opts)
+	case "text", "":
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		w.Close()
+		return nil, nil, fmt.Errorf("unknown log format %q (use text or json)", cfg.LogFormat)
+	}
+
+	return slog.New(handler), w, nil
+}
+
+// ParseLevel converts debug, info, warn or error to a slog level
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return slog.LevelInfo, fmt.Errorf("unknown log level %q (use debug, info, warn or error)", s)
+	}
+	return level, nil
+}
+
+func openOutput(cfg *config.Config) (Output, error) {
+	switch cfg.LogFile {
+	case "":
+		return consoleOutput{io.Discard}, nil
+	case "stdout":
+		return consoleOutput{os.Stdout}, nil
+	case "stderr":
+		return consoleOutput{os.Stderr}, nil
+	}
+
+	w := &RotatingWriter{
+		Path:       cfg.LogFile,
+		MaxSize:    int64(cfg.LogMaxSizeMB) * 1024 * 1024,
+		MaxAge:     cfg.LogRotateInterval,
+		MaxBackups: cfg.LogMaxBackups,
+		Compress:   cfg.LogCompress,
+	}
+	if err := w.Open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// consoleOutput writes to a stream the process does not own
+type consoleOutput struct {
+	io.Writer
+}
+
+func (consoleOutput) Sync() error  { return nil }
+func (consoleOutput) Close() error { return nil }
@@ -0,0 +1,295 @@
+package logging
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is appended to rotated files; it sorts chronologically
+const backupTimeFormat = "20060102-150405.000000000"
+
+// backupSuffix matches the suffix of rotated files, compressed or not
+var backupSuffix = regexp.MustCompile(`^\.(\d{8}-\d{6}\.\d{9})(\.gz)?$`)
+
+// RotatingWriter is a log file that rotates by size and age, compresses
+// rotated files and keeps a limited number of them. It is safe for
+// concurrent use.
+type RotatingWriter struct {
+	// Path of the active log file
+	Path string
+	// MaxSize in bytes before rotating (0 = no size limit)
+	MaxSize int64
+	// MaxAge of the active file before rotating (0 = no time limit)
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated files to keep (0 = keep all)
+	MaxBackups int
+	// Compress rotated files with gzip
+	Compress bool
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	closed   bool
+
+	// background compression of rotated files, one rotation at a time
+	wg      sync.WaitGroup
+	bgMu    sync.Mutex
+	errMu   sync.Mutex
+	lastErr error
+}
+
+// Open opens (or creates) the active log file
+func (r *RotatingWriter) Open() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.openLocked()
+}
+
+func (r *RotatingWriter) openLocked() error {
+	f, err := os.OpenFile(r.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	r.file = f
+	r.size = info.Size()
+	r.openedAt = r.startedAt(info)
+	return nil
+}
+
+// startedAt returns when the active file was started, so the age limit
+// survives restarts: the time of the last rotation, or the modification
+// time of a file that was never rotated
+func (r *RotatingWriter) startedAt(info os.FileInfo) time.Time {
+	if info.Size() == 0 {
+		return time.Now()
+	}
+	backups, err := r.Backups()
+	if err != nil || len(backups) == 0 {
+		return info.ModTime()
+	}
+	m := backupSuffix.FindStringSubmatch(strings.TrimPrefix(backups[len(backups)-1], r.Path))
+	t, err := time.ParseInLocation(backupTimeFormat, m[1], time.Local)
+	if err != nil {
+		return info.ModTime()
+	}
+	return t
+}
+
+// Write appends p to the active file, rotating first if it is due
+func (r *RotatingWriter) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return 0, os.ErrClosed
+	}
+	if r.file == nil {
+		if err := r.openLocked(); err != nil {
+			return 0, err
+		}
+	}
+
+	if r.dueLocked(int64(len(p))) {
+		if err := r.rotateLocked(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *RotatingWriter) dueLocked(next int64) bool {
+	if r.size == 0 {
+		return false
+	}
+	if r.MaxSize > 0 && r.size+next > r.MaxSize {
+		return true
+	}
+	return r.MaxAge > 0 && time.Since(r.openedAt) >= r.MaxAge
+}
+
+// Rotate forces a rotation of the active file
+func (r *RotatingWriter) Rotate() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return os.ErrClosed
+	}
+	return r.rotateLocked()
+}
+
+func (r *RotatingWriter) rotateLocked() error {
+	if r.file != nil {
+		if err := r.file.Close(); err != nil {
+			return err
+		}
+		r.file = nil
+	}
+
+	backup := r.Path + "." + time.Now().Format(backupTimeFormat)
+	if err := os.Rename(r.Path, backup); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := r.openLocked(); err != nil {
+		return err
+	}
+
+	// Compress and prune in the background so writers are not blocked
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.bgMu.Lock()
+		defer r.bgMu.Unlock()
+
+		if r.Compress {
+			if err := compressFile(backup); err != nil {
+				r.setErr(err)
+			}
+		}
+		if err := r.prune(); err != nil {
+			r.setErr(err)
+		}
+	}()
+	return nil
+}
+
+// Backups returns the rotated files, oldest first
+func (r *RotatingWriter) Backups() ([]string, error) {
+	matches, err := filepath.Glob(r.Path + ".*")
+	if err != nil {
+		return nil, err
+	}
+
+	// Other files next to the log and half-written compressed files are
+	// not backups
+	var backups []string
+	for _, m := range matches {
+		if backupSuffix.MatchString(strings.TrimPrefix(m, r.Path)) {
+			backups = append(backups, m)
+		}
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// prune removes the oldest backups beyond MaxBackups
+func (r *RotatingWriter) prune() error {
+	if r.MaxBackups <= 0 {
+		return nil
+	}
+
+	backups, err := r.Backups()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for len(backups) > r.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+		backups = backups[1:]
+	}
+	return errors.Join(errs...)
+}
+
+// Sync waits for background compression and flushes the active file to disk
+func (r *RotatingWriter) Sync() error {
+	// Holding mu prevents new rotations while waiting; background work never takes it
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.wg.Wait()
+
+	var err error
+	if r.file != nil {
+		err = r.file.Sync()
+	}
+	return errors.Join(err, r.takeErr())
+}
+
+// Close flushes and closes the active file; later writes fail
+func (r *RotatingWriter) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.wg.Wait()
+
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+
+	var errs []error
+	if r.file != nil {
+		errs = append(errs, r.file.Sync(), r.file.Close())
+		r.file = nil
+	}
+	errs = append(errs, r.takeErr())
+	return errors.Join(errs...)
+}
+
+func (r *RotatingWriter) setErr(err error) {
+	r.errMu.Lock()
+	defer r.errMu.Unlock()
+	r.lastErr = errors.Join(r.lastErr, err)
+}
+
+func (r *RotatingWriter) takeErr() error {
+	r.errMu.Lock()
+	defer r.errMu.Unlock()
+	err := r.lastErr
+	r.lastErr = nil
+	return err
+}
+
+// compressFile gzips path to path.gz and removes the original
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// Already pruned by retention
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := path + ".gz.tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("compress %s: %w", path, err)
+	}
+	if err := errors.Join(gz.Close(), dst.Close()); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("compress %s: %w", path, err)
+	}
+
+	if err := os.Rename(tmp, path+".gz"); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"time"
+
+	"gold-analyzer/analysis"
+	"gold-analyzer/strategy"
+)
+
+// Default is the registry served on /metrics
+var Default = NewRegistry()
+
+// latencyBuckets covers fast responses up to the full Yahoo retry budget
+var latencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Analyzer metrics
+var (
+	LastPrice = Default.NewGauge("gold_analyzer_last_price", "Last close price.", "symbol")
+	RSI       = Default.NewGauge("gold_analyzer_rsi", "Last RSI value.", "symbol")
+	MACDHist  = Default.NewGauge("gold_analyzer_macd_histogram", "Last MACD histogram value.", "symbol")
+	ATR       = Default.NewGauge("gold_analyzer_atr", "Last ATR value.", "symbol")
+	Signal    = Default.NewGauge("gold_analyzer_signal", "Current signal (1 = BUY, 0 = HOLD, -1 = SELL).", "symbol")
+
+	FetchAttempts    = Default.NewCounter("gold_analyzer_fetch_attempts_total", "HTTP requests sent to Yahoo Finance.", "symbol")
+	FetchRateLimited = Default.NewCounter("gold_analyzer_fetch_rate_limited_total", "Responses with status 429.", "symbol")
+	FetchRetries     = Default.NewCounter("gold_analyzer_fetch_retries_total", "Retried fetch attempts.", "symbol")
+	FetchErrors      = Default.NewCounter("gold_analyzer_fetch_errors_total", "Fetches that failed after all retries.", "symbol")
+
+	FetchDuration    = Default.NewHistogram("gold_analyzer_fetch_duration_seconds", "Time to fetch candles including retries.", latencyBuckets, "symbol")
+	AnalysisDuration = Default.NewHistogram("gold_analyzer_analysis_duration_seconds", "Time of a full analysis run.", latencyBuckets, "symbol")
+)
+
+// ObserveResult updates the indicator and signal gauges from an analysis result
+func ObserveResult(r analysis.Result) {
+	LastPrice.Set(r.Price, r.Symbol)
+	RSI.Set(r.Indicators.RSI, r.Symbol)
+	MACDHist.Set(r.Indicators.MACDHist, r.Symbol)
+	ATR.Set(r.Indicators.ATR, r.Symbol)
+	Signal.Set(SignalValue(r.Signal), r.Symbol)
+}
+
+// SignalValue maps a signal to a numeric gauge value
+func SignalValue(s strategy.Signal) float64 {
+	switch s {
+	case strategy.BUY:
+		return 1
+	case strategy.SELL:
+		return -1
+	}
+	return 0
+}
+
+// Since returns the seconds elapsed since start, for histogram observations
+func Since(start time.Time) float64 {
+	return time.Since(start).Seconds()
+}
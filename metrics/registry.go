@@ -0,0 +1,276 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// collector is a metric family that can write itself in the Prometheus text format
+type collector interface {
+	write(w io.Writer)
+}
+
+// Registry holds metric families and renders them for scraping
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, c)
+}
+
+// Write writes all metrics in the Prometheus text exposition format
+func (r *Registry) Write(w io.Writer) {
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.write(bw)
+	}
+	bw.Flush()
+}
+
+// Handler serves the registry on /metrics
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.Write(w)
+	})
+}
+
+// series is one labelled time series of a family
+type series struct {
+	labels []string
+	value  float64
+	// histogram only
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// family is shared by all metric types
+type family struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+func newFamily(name, help, kind string, labels []string) *family {
+	return &family{name: name, help: help, kind: kind, labels: labels, series: make(map[string]*series)}
+}
+
+func (f *family) get(labelValues []string) *series {
+	s, ok := f.lookup(labelValues)
+	if !ok {
+		s = &series{labels: append([]string(nil), labelValues...)}
+		f.series[strings.Join(labelValues, "\xff")] = s
+	}
+	return s
+}
+
+// lookup finds a series without creating it
+func (f *family) lookup(labelValues []string) (*series, bool) {
+	if len(labelValues) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", f.name, len(f.labels), len(labelValues)))
+	}
+	s, ok := f.series[strings.Join(labelValues, "\xff")]
+	return s, ok
+}
+
+// sorted returns the series ordered by label values for stable output
+func (f *family) sorted() []*series {
+	keys := make([]string, 0, len(f.series))
+	for k := range f.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]*series, len(keys))
+	for i, k := range keys {
+		out[i] = f.series[k]
+	}
+	return out
+}
+
+// Escapes of the Prometheus text format; Go escapes such as \x.. are not
+// valid there
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+)
+
+func (f *family) header(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, helpEscaper.Replace(f.help), f.name, f.kind)
+}
+
+func (f *family) labelString(values []string, extra ...string) string {
+	var parts []string
+	for i, name := range f.labels {
+		parts = append(parts, name+`="`+labelEscaper.Replace(values[i])+`"`)
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		parts = append(parts, extra[i]+`="`+labelEscaper.Replace(extra[i+1])+`"`)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Gauge is a value that can go up and down
+type Gauge struct {
+	f *family
+}
+
+// NewGauge creates and registers a gauge family
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	g := &Gauge{f: newFamily(name, help, "gauge", labels)}
+	r.register(g)
+	return g
+}
+
+// Set sets the gauge for the given label values
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	g.f.mu.Lock()
+	defer g.f.mu.Unlock()
+	g.f.get(labelValues).value = v
+}
+
+func (g *Gauge) write(w io.Writer) {
+	g.f.mu.Lock()
+	defer g.f.mu.Unlock()
+
+	g.f.header(w)
+	for _, s := range g.f.sorted() {
+		fmt.Fprintf(w, "%s%s %s\n", g.f.name, g.f.labelString(s.labels), formatFloat(s.value))
+	}
+}
+
+// Counter is a monotonically increasing value
+type Counter struct {
+	f *family
+}
+
+// NewCounter creates and registers a counter family
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{f: newFamily(name, help, "counter", labels)}
+	r.register(c)
+	return c
+}
+
+// Inc increments the counter for the given label values
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds v (must be >= 0) to the counter
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+	c.f.mu.Lock()
+	defer c.f.mu.Unlock()
+	c.f.get(labelValues).value += v
+}
+
+// Value returns the current counter value, mainly for tests. Reading a
+// series that was never incremented returns 0 and does not export it.
+func (c *Counter) Value(labelValues ...string) float64 {
+	c.f.mu.Lock()
+	defer c.f.mu.Unlock()
+	if s, ok := c.f.lookup(labelValues); ok {
+		return s.value
+	}
+	return 0
+}
+
+func (c *Counter) write(w io.Writer) {
+	c.f.mu.Lock()
+	defer c.f.mu.Unlock()
+
+	c.f.header(w)
+	for _, s := range c.f.sorted() {
+		fmt.Fprintf(w, "%s%s %s\n", c.f.name, c.f.labelString(s.labels), formatFloat(s.value))
+	}
+}
+
+// Histogram counts observations in cumulative buckets
+type Histogram struct {
+	f       *family
+	buckets []float64
+}
+
+// NewHistogram creates and registers a histogram family with upper bounds buckets
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	b := append([]float64(nil), buckets...)
+	sort.Float64s(b)
+	h := &Histogram{f: newFamily(name, help, "histogram", labels), buckets: b}
+	r.register(h)
+	return h
+}
+
+// Observe adds an observation for the given label values
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	h.f.mu.Lock()
+	defer h.f.mu.Unlock()
+
+	s := h.f.get(labelValues)
+	if s.counts == nil {
+		s.counts = make([]uint64, len(h.buckets))
+	}
+	for i, upper := range h.buckets {
+		if v <= upper {
+			s.counts[i]++
+		}
+	}
+	s.sum += v
+	s.count++
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.f.mu.Lock()
+	defer h.f.mu.Unlock()
+
+	h.f.header(w)
+	for _, s := range h.f.sorted() {
+		for i, upper := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.f.name, h.f.labelString(s.labels, "le", formatFloat(upper)), s.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.f.name, h.f.labelString(s.labels, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.f.name, h.f.labelString(s.labels), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.f.name, h.f.labelString(s.labels), s.count)
+	}
+}
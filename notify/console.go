@@ -0,0 +1,32 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"gold-analyzer/i18n"
+)
+
+// ConsoleNotifier prints events to a writer (stdout by default)
+type ConsoleNotifier struct {
+	Out io.Writer
+}
+
+// NewConsoleNotifier creates a console notifier writing to stdout
+func NewConsoleNotifier() *ConsoleNotifier {
+	return &ConsoleNotifier{Out: os.Stdout}
+}
+
+// Name implements Notifier
+func (c *ConsoleNotifier) Name() string {
+	return "console"
+}
+
+// Notify implements Notifier
+func (c *ConsoleNotifier) Notify(ctx context.Context, e Event) error {
+	_, err := fmt.Fprintf(c.Out, "🔔 [%s] %s %s @ %.2f: %s\n",
+		i18n.Time(e.Time), e.Symbol, e.Signal, e.Price, e.Message)
+	return err
+}
@@ -0,0 +1,223 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned when a channel exceeded its rate limit
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// ErrDropped is wrapped by the errors of deliveries that were given up:
+// out of attempts or for a channel that is no longer registered
+var ErrDropped = errors.New("notification dropped")
+
+var errNotRegistered = errors.New("channel not registered")
+
+const (
+	// DefaultMaxAttempts is how many times a delivery is tried before it is dropped
+	DefaultMaxAttempts = 5
+	// baseBackoff is the delay before the first retry, doubled on every attempt
+	baseBackoff = 30 * time.Second
+)
+
+type channel struct {
+	notifier Notifier
+	limiter  *RateLimiter
+}
+
+// Dispatcher fans events out to all registered notifiers concurrently.
+// Failed or rate limited deliveries are put on the retry queue.
+type Dispatcher struct {
+	mu          sync.RWMutex
+	channels    map[string]*channel
+	order       []string
+	queue       *Queue
+	MaxAttempts int
+}
+
+// NewDispatcher creates a dispatcher using queue for failed deliveries
+func NewDispatcher(queue *Queue) *Dispatcher {
+	if queue == nil {
+		queue, _ = NewQueue("")
+	}
+	return &Dispatcher{
+		channels:    make(map[string]*channel),
+		queue:       queue,
+		MaxAttempts: DefaultMaxAttempts,
+	}
+}
+
+// Register adds a notifier with an optional rate limiter (nil = unlimited)
+func (d *Dispatcher) Register(n Notifier, limiter *RateLimiter) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.channels[n.Name()]; !ok {
+		d.order = append(d.order, n.Name())
+	}
+	d.channels[n.Name()] = &channel{notifier: n, limiter: limiter}
+}
+
+// Unregister removes a notifier by name. Its queued deliveries are
+// dropped on the next retry.
+func (d *Dispatcher) Unregister(name string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.channels[name]; !ok {
+		return
+	}
+	delete(d.channels, name)
+	for i, n := range d.order {
+		if n == name {
+			d.order = append(d.order[:i], d.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Channels returns the names of the registered notifiers
+func (d *Dispatcher) Channels() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return append([]string(nil), d.order...)
+}
+
+// Queue returns the retry queue
+func (d *Dispatcher) Queue() *Queue {
+	return d.queue
+}
+
+// Dispatch sends the event to every channel concurrently and waits for all
+// of them. Failures are queued for retry; the returned error joins them.
+func (d *Dispatcher) Dispatch(ctx context.Context, e Event) error {
+	d.mu.RLock()
+	channels := make([]*channel, 0, len(d.order))
+	for _, name := range d.order {
+		channels = append(channels, d.channels[name])
+	}
+	d.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(channels))
+	for i, ch := range channels {
+		wg.Add(1)
+		go func(i int, ch *channel) {
+			defer wg.Done()
+			errs[i] = d.deliver(ctx, ch, Delivery{Notifier: ch.notifier.Name(), Event: e})
+		}(i, ch)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// RetryPending retries queued deliveries that are due
+func (d *Dispatcher) RetryPending(ctx context.Context) error {
+	var errs []error
+	for _, item := range d.queue.Due(time.Now()) {
+		d.mu.RLock()
+		ch, ok := d.channels[item.Notifier]
+		d.mu.RUnlock()
+
+		if !ok {
+			// Channel was removed from the configuration
+			errs = append(errs, drop(item, errNotRegistered))
+			continue
+		}
+		errs = append(errs, d.deliver(ctx, ch, item))
+	}
+	if err := d.queue.Save(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// Flush makes a last delivery attempt for every queued item, ignoring
+// backoff, and persists whatever is still pending. Used on shutdown.
+func (d *Dispatcher) Flush(ctx context.Context) error {
+	var errs []error
+	for _, item := range d.queue.Due(time.Now().Add(24 * 365 * time.Hour)) {
+		if ctx.Err() != nil {
+			// Out of time, keep the rest for the next run
+			if err := d.queue.Push(item); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		d.mu.RLock()
+		ch, ok := d.channels[item.Notifier]
+		d.mu.RUnlock()
+		if !ok {
+			errs = append(errs, drop(item, errNotRegistered))
+			continue
+		}
+		errs = append(errs, d.deliver(ctx, ch, item))
+	}
+
+	if err := d.queue.Save(); err != nil {
+		errs = append(errs, err)
+	}
+	if n := d.queue.Len(); n > 0 {
+		errs = append(errs, fmt.Errorf("%d notification(s) still pending", n))
+	}
+	return errors.Join(errs...)
+}
+
+// deliver sends a single delivery and queues it for retry on failure
+func (d *Dispatcher) deliver(ctx context.Context, ch *channel, item Delivery) error {
+	if !ch.limiter.Allow() {
+		return d.requeue(item, ErrRateLimited)
+	}
+
+	if err := ch.notifier.Notify(ctx, item.Event); err != nil {
+		if ctx.Err() != nil {
+			// Cut off by shutdown, not a failure of the channel
+			return d.requeue(item, errors.Join(err, errInterrupted))
+		}
+		return d.requeue(item, err)
+	}
+	return nil
+}
+
+// errInterrupted marks deliveries whose context was cancelled
+var errInterrupted = errors.New("interrupted")
+
+// requeue schedules the next attempt with exponential backoff
+func (d *Dispatcher) requeue(item Delivery, cause error) error {
+	item.LastError = cause.Error()
+
+	switch {
+	case errors.Is(cause, errInterrupted):
+		// Retried as soon as possible and without using up an attempt
+		item.NextAttempt = time.Now()
+	case errors.Is(cause, ErrRateLimited):
+		// Rate limited deliveries do not count as failed attempts
+		item.NextAttempt = time.Now().Add(baseBackoff)
+	default:
+		item.Attempts++
+		if d.MaxAttempts > 0 && item.Attempts >= d.MaxAttempts {
+			return drop(item, cause)
+		}
+		item.NextAttempt = time.Now().Add(baseBackoff * time.Duration(1<<uint(item.Attempts)))
+	}
+
+	if err := d.queue.Push(item); err != nil {
+		return errors.Join(fmt.Errorf("%s: %w", item.Notifier, cause), err)
+	}
+	return fmt.Errorf("%s: %w", item.Notifier, cause)
+}
+
+// drop logs a delivery that will not be retried and returns its error
+func drop(item Delivery, cause error) error {
+	slog.Warn("dropping notification",
+		"channel", item.Notifier, "event", item.Event.ID, "attempts", item.Attempts, "error", cause)
+	return fmt.Errorf("%s: %w: %s after %d attempts: %w",
+		item.Notifier, ErrDropped, item.Event.ID, item.Attempts, cause)
+}
@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Event is a single alert delivered to every notification channel
+type Event struct {
+	// ID identifies the event across retries and restarts
+	ID string `json:"id"`
+	// Time the event was raised
+	Time time.Time `json:"time"`
+	// Kind of event (signal, alert, ...)
+	Kind string `json:"kind"`
+	// Symbol the event refers to
+	Symbol string `json:"symbol"`
+	// Signal is the strategy signal, if any
+	Signal string `json:"signal,omitempty"`
+	// Price at the time of the event
+	Price float64 `json:"price"`
+	// Message is a human readable description
+	Message string `json:"message"`
+}
+
+// NewEvent creates an event with a generated ID
+func NewEvent(kind, symbol, message string, price float64) Event {
+	now := time.Now()
+	return Event{
+		ID:      fmt.Sprintf("%s-%s-%d", kind, symbol, now.UnixNano()),
+		Time:    now,
+		Kind:    kind,
+		Symbol:  symbol,
+		Price:   price,
+		Message: message,
+	}
+}
+
+// Notifier delivers events to a single channel (console, webhook, ...)
+type Notifier interface {
+	// Name returns a unique channel name, used for rate limits and the retry queue
+	Name() string
+	// Notify delivers the event or returns an error if it should be retried
+	Notify(ctx context.Context, e Event) error
+}
@@ -0,0 +1,120 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"gold-analyzer/atomicfile"
+)
+
+// Delivery is a failed event waiting to be retried on one channel
+type Delivery struct {
+	Notifier    string    `json:"notifier"`
+	Event       Event     `json:"event"`
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"next_attempt"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// Queue holds failed deliveries and persists them to a file so they
+// survive restarts. An empty path keeps the queue in memory only.
+type Queue struct {
+	mu    sync.Mutex
+	path  string
+	items []Delivery
+}
+
+// NewQueue creates a queue backed by path and loads any pending deliveries.
+// A file that cannot be parsed is moved aside with a .corrupt suffix, so
+// the next save does not overwrite it; the queue then starts empty.
+func NewQueue(path string) (*Queue, error) {
+	q := &Queue{path: path}
+	if path == "" {
+		return q, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return q, nil
+	}
+	if err != nil {
+		return q, err
+	}
+	if len(data) == 0 {
+		return q, nil
+	}
+	if err := json.Unmarshal(data, &q.items); err != nil {
+		q.items = nil
+		aside := path + ".corrupt"
+		if rerr := os.Rename(path, aside); rerr != nil {
+			// Keep the file and the queue in memory rather than lose it
+			q.path = ""
+			return q, fmt.Errorf("queue file %s: %w (not persisting the queue: %v)", path, err, rerr)
+		}
+		return q, fmt.Errorf("queue file %s: %w (moved to %s)", path, err, aside)
+	}
+	return q, nil
+}
+
+// Push adds a delivery to the queue
+func (q *Queue) Push(d Delivery) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.items = append(q.items, d)
+	return q.saveLocked()
+}
+
+// Due removes and returns the deliveries whose retry time has come
+func (q *Queue) Due(now time.Time) []Delivery {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var due, rest []Delivery
+	for _, d := range q.items {
+		if !d.NextAttempt.After(now) {
+			due = append(due, d)
+		} else {
+			rest = append(rest, d)
+		}
+	}
+	q.items = rest
+	return due
+}
+
+// Len returns the number of pending deliveries
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// Pending returns a copy of the pending deliveries
+func (q *Queue) Pending() []Delivery {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]Delivery(nil), q.items...)
+}
+
+// Save writes the pending deliveries to disk
+func (q *Queue) Save() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.saveLocked()
+}
+
+func (q *Queue) saveLocked() error {
+	if q.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(q.items, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.WriteFile(q.path, data)
+}
@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket limiting deliveries per channel
+type RateLimiter struct {
+	mu       sync.Mutex
+	rate     float64 // tokens per second
+	burst    float64
+	tokens   float64
+	lastFill time.Time
+}
+
+// NewRateLimiter allows perMinute deliveries per minute with the given burst.
+// A non-positive perMinute disables limiting.
+func NewRateLimiter(perMinute, burst int) *RateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:     float64(perMinute) / 60,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		lastFill: time.Now(),
+	}
+}
+
+// Allow reports whether a delivery may happen now and consumes a token if so
+func (r *RateLimiter) Allow() bool {
+	if r == nil {
+		return true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.tokens += now.Sub(r.lastFill).Seconds() * r.rate
+	if r.tokens > r.burst {
+		r.tokens = r.burst
+	}
+	r.lastFill = now
+
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
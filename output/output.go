@@ -0,0 +1,128 @@
+// Package output renders analysis results in machine-readable formats so
+// the analyzer can be piped into jq, spreadsheets and other tools.
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gold-analyzer/analysis"
+)
+
+// Output formats
+const (
+	Console = "console"
+	JSON    = "json"
+	NDJSON  = "ndjson"
+	CSV     = "csv"
+	// TUI is the full-screen dashboard drawn by package tui
+	TUI = "tui"
+)
+
+// Renderer writes one analysis result per run
+type Renderer interface {
+	Render(res analysis.Result) error
+}
+
+// ParseFormat validates an OUTPUT_FORMAT value; empty means Console
+func ParseFormat(s string) (string, error) {
+	switch f := strings.ToLower(strings.TrimSpace(s)); f {
+	case "":
+		return Console, nil
+	case Console, JSON, NDJSON, CSV, TUI:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown output format %q (use console, json, ndjson, csv or tui)", s)
+}
+
+// New creates a renderer for a machine-readable format. The decorated
+// console output and the dashboard are not Renderers.
+func New(format string, w io.Writer) (Renderer, error) {
+	f, err := ParseFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	switch f {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return &jsonRenderer{enc: enc}, nil
+	case NDJSON:
+		return &jsonRenderer{enc: json.NewEncoder(w)}, nil
+	case CSV:
+		return &csvRenderer{w: csv.NewWriter(w)}, nil
+	}
+	return nil, fmt.Errorf("%s output is not machine-readable", f)
+}
+
+// jsonRenderer writes each result as a JSON object (indented or one line)
+type jsonRenderer struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func (r *jsonRenderer) Render(res analysis.Result) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.enc.Encode(res)
+}
+
+// Header is the CSV column order
+var Header = []string{
+	"time", "symbol", "interval", "candle_time", "price", "change", "change_percent",
+	"rsi", "macd", "macd_signal", "macd_hist", "atr", "signal",
+}
+
+// csvRenderer writes a header once and then one row per result
+type csvRenderer struct {
+	mu          sync.Mutex
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func (r *csvRenderer) Render(res analysis.Result) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.wroteHeader {
+		if err := r.w.Write(Header); err != nil {
+			return err
+		}
+		r.wroteHeader = true
+	}
+
+	ind := res.Indicators
+	row := []string{
+		res.Time.Format(time.RFC3339),
+		res.Symbol,
+		res.Interval,
+		res.CandleTime.Format(time.RFC3339),
+		formatFloat(res.Price),
+		formatFloat(res.Change),
+		formatFloat(res.ChangePercent),
+		formatFloat(ind.RSI),
+		formatFloat(ind.MACD),
+		formatFloat(ind.MACDSignal),
+		formatFloat(ind.MACDHist),
+		formatFloat(ind.ATR),
+		string(res.Signal),
+	}
+	if err := r.w.Write(row); err != nil {
+		return err
+	}
+
+	// Flush every row so consumers see results as they happen
+	r.w.Flush()
+	return r.w.Error()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,200 @@
+// Package report renders a self-contained HTML report with inline SVG
+// charts. The output needs no JavaScript or external assets to view.
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	"gold-analyzer/analysis"
+	"gold-analyzer/config"
+	"gold-analyzer/i18n"
+	"gold-analyzer/strategy"
+)
+
+// Chart heights in SVG user units
+const (
+	priceHeight = 320
+	paneHeight  = 160
+)
+
+type page struct {
+	Lang      string
+	Dir       string
+	Title     string
+	Subtitle  string
+	Summary   [][2]string
+	Price     template.HTML
+	RSI       template.HTML
+	MACD      template.HTML
+	ATR       template.HTML
+	Markers   []markerRow
+	Labels    map[string]string
+	Generated string
+}
+
+type markerRow struct {
+	Time   string
+	Signal string
+	Price  string
+	Class  string
+}
+
+// Write renders the report for a series computed with analysis.BuildSeries
+func Write(w io.Writer, cfg *config.Config, s analysis.Series, generated time.Time) error {
+	if len(s.Closes) == 0 {
+		return fmt.Errorf("empty series")
+	}
+
+	p := i18n.Default()
+	dir := "ltr"
+	if p.RTL() {
+		dir = "rtl"
+	}
+
+	first, last := 0, len(s.Closes)-1
+	start, end := i18n.Time(s.Times[first]), i18n.Time(s.Times[last])
+	markers := s.Markers()
+
+	var buys, sells int
+	rows := make([]markerRow, 0, len(markers))
+	for _, m := range markers {
+		row := markerRow{Time: i18n.Time(m.Time), Signal: string(m.Signal), Price: fmt.Sprintf("%.2f", m.Price), Class: "buy"}
+		if m.Signal == strategy.BUY {
+			buys++
+		} else {
+			sells++
+			row.Class = "sell"
+		}
+		rows = append(rows, row)
+	}
+
+	lo, hi := bounds(s.Closes, 0)
+	change := s.Closes[last] - s.Closes[first]
+	lastSignal := s.Signals[last]
+	if lastSignal == "" {
+		lastSignal = strategy.HOLD
+	}
+
+	data := page{
+		Lang:     p.Lang(),
+		Dir:      dir,
+		Title:    p.T("report.title", cfg.Symbol),
+		Subtitle: p.T("report.period", cfg.Interval, cfg.Range, start, end),
+		Summary: [][2]string{
+			{p.T("report.last_price"), fmt.Sprintf("%.2f", s.Closes[last])},
+			{p.T("report.change"), fmt.Sprintf("%+.2f (%+.2f%%)", change, change/s.Closes[first]*100)},
+			{p.T("report.range"), fmt.Sprintf("%.2f – %.2f", lo, hi)},
+			{p.T("report.signals"), fmt.Sprintf("BUY %d / SELL %d", buys, sells)},
+			{p.T("report.last_signal"), string(lastSignal)},
+		},
+		Price:   priceChart(s, markers, start, end),
+		RSI:     rsiChart(cfg, s, start, end),
+		MACD:    macdChart(s, start, end),
+		ATR:     atrChart(s, start, end),
+		Markers: rows,
+		Labels: map[string]string{
+			"price":   p.T("report.price_chart"),
+			"rsi":     p.T("report.rsi_chart", cfg.RSIPeriod),
+			"macd":    p.T("report.macd_chart"),
+			"atr":     p.T("report.atr_chart", cfg.ATRPeriod),
+			"markers": p.T("report.markers"),
+			"time":    p.T("report.time"),
+			"signal":  p.T("report.signal"),
+			"price_c": p.T("report.price"),
+			"none":    p.T("report.no_markers"),
+		},
+		Generated: p.T("report.generated", i18n.Time(generated)),
+	}
+	return pageTemplate.Execute(w, data)
+}
+
+func priceChart(s analysis.Series, markers []analysis.Marker, start, end string) template.HTML {
+	lo, hi := bounds(s.Closes, 0)
+	// Leave room for the markers above and below the line
+	margin := (hi - lo) * 0.08
+	pl := newPlot(priceHeight, len(s.Closes), lo-margin, hi+margin)
+	pl.axis(ticks(lo, hi, 5), "%.2f", start, end)
+	pl.line(s.Closes, 0, colorLine)
+	for _, m := range markers {
+		title := fmt.Sprintf("%s %.2f", m.Signal, m.Price)
+		pl.marker(m.Index, m.Price, m.Signal == strategy.BUY, title)
+	}
+	return pl.svg()
+}
+
+func rsiChart(cfg *config.Config, s analysis.Series, start, end string) template.HTML {
+	pl := newPlot(paneHeight, len(s.RSI), 0, 100)
+	pl.axis([]float64{0, 30, 50, 70, 100}, "%.0f", start, end)
+	pl.band(cfg.RSIBuyLower, cfg.RSIBuyUpper, colorBand)
+	pl.threshold(cfg.RSISellThreshold, colorSell)
+	pl.line(s.RSI, cfg.RSIPeriod+1, colorLine)
+	return pl.svg()
+}
+
+func macdChart(s analysis.Series, start, end string) template.HTML {
+	lo, hi := bounds(s.MACDHist, 0)
+	peak := max(-lo, hi)
+	pl := newPlot(paneHeight, len(s.MACDHist), -peak, peak)
+	pl.axis([]float64{-peak, 0, peak}, "%.3f", start, end)
+	pl.bars(s.MACDHist, 0)
+	return pl.svg()
+}
+
+func atrChart(s analysis.Series, start, end string) template.HTML {
+	from := s.WarmUp - 1
+	lo, hi := bounds(s.ATR, from)
+	pl := newPlot(paneHeight, len(s.ATR), lo, hi)
+	pl.axis(ticks(lo, hi, 3), "%.2f", start, end)
+	pl.line(s.ATR, from, colorATR)
+	return pl.svg()
+}
+
+var pageTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}" dir="{{.Dir}}">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, -apple-system, "Segoe UI", Tahoma, sans-serif; margin: 2rem auto; max-width: 1000px; color: #24292f; }
+h1 { margin-bottom: .25rem; }
+.sub, footer { color: #57606a; }
+table { border-collapse: collapse; margin: 1rem 0; }
+td, th { padding: .3rem .8rem; border-bottom: 1px solid #d0d7de; text-align: start; }
+section { margin: 1.5rem 0; }
+.buy { color: #1a7f37; font-weight: bold; }
+.sell { color: #cf222e; font-weight: bold; }
+svg { direction: ltr; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="sub">{{.Subtitle}}</p>
+<table>
+{{- range .Summary}}
+<tr><th>{{index . 0}}</th><td>{{index . 1}}</td></tr>
+{{- end}}
+</table>
+<section><h2>{{.Labels.price}}</h2>{{.Price}}</section>
+<section><h2>{{.Labels.rsi}}</h2>{{.RSI}}</section>
+<section><h2>{{.Labels.macd}}</h2>{{.MACD}}</section>
+<section><h2>{{.Labels.atr}}</h2>{{.ATR}}</section>
+<section>
+<h2>{{.Labels.markers}}</h2>
+{{- if .Markers}}
+<table>
+<tr><th>{{.Labels.time}}</th><th>{{.Labels.signal}}</th><th>{{.Labels.price_c}}</th></tr>
+{{- range .Markers}}
+<tr><td>{{.Time}}</td><td class="{{.Class}}">{{.Signal}}</td><td>{{.Price}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>{{.Labels.none}}</p>
+{{- end}}
+</section>
+<footer>{{.Generated}}</footer>
+</body>
+</html>
+`))
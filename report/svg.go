@@ -0,0 +1,158 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"math"
+	"strings"
+)
+
+// Chart geometry in SVG user units
+const (
+	chartWidth = 960
+	padLeft    = 64
+	padRight   = 16
+	padTop     = 12
+	padBottom  = 24
+)
+
+// Colors shared by all charts
+const (
+	colorLine = "#b8860b"
+	colorBuy  = "#1a7f37"
+	colorSell = "#cf222e"
+	colorGrid = "#d0d7de"
+	colorText = "#57606a"
+	colorATR  = "#0969da"
+	colorBand = "#1a7f37"
+)
+
+// plot maps series indexes and values to SVG coordinates
+type plot struct {
+	height float64
+	n      int
+	lo, hi float64
+	b      strings.Builder
+}
+
+func newPlot(height float64, n int, lo, hi float64) *plot {
+	if hi == lo {
+		hi, lo = hi+1, lo-1
+	}
+	p := &plot{height: height, n: n, lo: lo, hi: hi}
+	fmt.Fprintf(&p.b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %.0f" width="100%%" role="img">`, chartWidth, height)
+	return p
+}
+
+func (p *plot) x(i int) float64 {
+	if p.n <= 1 {
+		return padLeft
+	}
+	return padLeft + float64(i)/float64(p.n-1)*(chartWidth-padLeft-padRight)
+}
+
+func (p *plot) y(v float64) float64 {
+	inner := p.height - padTop - padBottom
+	return padTop + (p.hi-v)/(p.hi-p.lo)*inner
+}
+
+// axis draws horizontal grid lines with value labels and the time labels
+func (p *plot) axis(values []float64, format string, start, end string) {
+	for _, v := range values {
+		y := p.y(v)
+		fmt.Fprintf(&p.b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="%s" stroke-width="1"/>`,
+			padLeft, y, chartWidth-padRight, y, colorGrid)
+		p.text(padLeft-6, y+4, "end", fmt.Sprintf(format, v))
+	}
+	p.text(padLeft, p.height-6, "start", start)
+	p.text(chartWidth-padRight, p.height-6, "end", end)
+}
+
+func (p *plot) text(x, y float64, anchor, s string) {
+	fmt.Fprintf(&p.b, `<text x="%.1f" y="%.1f" font-size="11" fill="%s" text-anchor="%s">%s</text>`,
+		x, y, colorText, anchor, html.EscapeString(s))
+}
+
+// line draws values from index from onwards as a polyline
+func (p *plot) line(values []float64, from int, color string) {
+	var pts strings.Builder
+	for i := from; i < len(values); i++ {
+		if math.IsNaN(values[i]) {
+			continue
+		}
+		fmt.Fprintf(&pts, "%.1f,%.1f ", p.x(i), p.y(values[i]))
+	}
+	fmt.Fprintf(&p.b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="1.5"/>`,
+		strings.TrimSpace(pts.String()), color)
+}
+
+// band shades the value range lo..hi
+func (p *plot) band(lo, hi float64, color string) {
+	fmt.Fprintf(&p.b, `<rect x="%d" y="%.1f" width="%d" height="%.1f" fill="%s" fill-opacity="0.12"/>`,
+		padLeft, p.y(hi), chartWidth-padLeft-padRight, p.y(lo)-p.y(hi), color)
+}
+
+// threshold draws a dashed horizontal line at v
+func (p *plot) threshold(v float64, color string) {
+	fmt.Fprintf(&p.b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="%s" stroke-dasharray="4 3"/>`,
+		padLeft, p.y(v), chartWidth-padRight, p.y(v), color)
+}
+
+// bars draws signed values as columns from zero
+func (p *plot) bars(values []float64, from int) {
+	w := math.Max(1, (chartWidth-padLeft-padRight)/float64(max(1, p.n))*0.8)
+	zero := p.y(0)
+	for i := from; i < len(values); i++ {
+		if math.IsNaN(values[i]) {
+			continue
+		}
+		y := p.y(values[i])
+		color := colorBuy
+		if values[i] < 0 {
+			color = colorSell
+		}
+		fmt.Fprintf(&p.b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`,
+			p.x(i)-w/2, math.Min(y, zero), w, math.Abs(zero-y), color)
+	}
+}
+
+// marker draws a triangle pointing up (buy) or down (sell) at a point
+func (p *plot) marker(i int, v float64, up bool, title string) {
+	x, y := p.x(i), p.y(v)
+	color, pts := colorBuy, fmt.Sprintf("%.1f,%.1f %.1f,%.1f %.1f,%.1f", x, y+4, x-6, y+14, x+6, y+14)
+	if !up {
+		color, pts = colorSell, fmt.Sprintf("%.1f,%.1f %.1f,%.1f %.1f,%.1f", x, y-4, x-6, y-14, x+6, y-14)
+	}
+	fmt.Fprintf(&p.b, `<polygon points="%s" fill="%s"><title>%s</title></polygon>`, pts, color, html.EscapeString(title))
+}
+
+func (p *plot) svg() template.HTML {
+	return template.HTML(p.b.String() + "</svg>")
+}
+
+// bounds returns the smallest and largest value from index from onwards,
+// skipping warm-up NaN values
+func bounds(values []float64, from int) (lo, hi float64) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, v := range values[from:] {
+		if math.IsNaN(v) {
+			continue
+		}
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	if math.IsInf(lo, 0) {
+		return 0, 0
+	}
+	return lo, hi
+}
+
+// ticks returns n evenly spaced values between lo and hi
+func ticks(lo, hi float64, n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = lo + (hi-lo)*float64(i)/float64(n-1)
+	}
+	return out
+}
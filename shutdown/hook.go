@@ -0,0 +1,106 @@
+package shutdown
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// progressInterval is how often the pending hooks are reported while a
+// hook runs
+const progressInterval = time.Second
+
+// ErrTimeout is wrapped by the errors of hooks that did not finish in time
+var ErrTimeout = errors.New("shutdown timeout exceeded")
+
+// Phase groups shutdown hooks; phases run in order, lowest first
+type Phase int
+
+const (
+	// PhaseStopIntake stops accepting new work (e.g. the API server)
+	PhaseStopIntake Phase = iota
+	// PhaseFlush delivers or persists pending work (e.g. notifications)
+	PhaseFlush
+	// PhaseClose closes storage and other resources
+	PhaseClose
+	// PhaseFinal writes final stats and syncs the logs
+	PhaseFinal
+)
+
+// Hook is a named function run during shutdown
+type Hook struct {
+	Name  string
+	Phase Phase
+	// Priority orders hooks within a phase, lowest first
+	Priority int
+	// Timeout bounds this hook; zero means the rest of the shutdown timeout
+	Timeout time.Duration
+	// Fn receives a context that is done when the hook's time is up
+	Fn func(ctx context.Context) error
+}
+
+// HookError is the failure of one hook
+type HookError struct {
+	Hook string
+	Err  error
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Hook, e.Err)
+}
+
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// ordered returns the hooks by phase and priority, keeping the
+// registration order of equal hooks
+func ordered(hooks []Hook) []Hook {
+	hooks = slices.Clone(hooks)
+	slices.SortStableFunc(hooks, func(a, b Hook) int {
+		if c := cmp.Compare(a.Phase, b.Phase); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Priority, b.Priority)
+	})
+	return hooks
+}
+
+// run calls h.Fn and waits until it returns or its time is up, calling
+// waiting every progressInterval meanwhile. A hook that does not return in
+// time is abandoned: its goroutine keeps running, and returned is called
+// whenever Fn does return.
+func (h Hook) run(deadline time.Time, waiting func(elapsed time.Duration), returned func()) error {
+	if h.Timeout > 0 {
+		if d := time.Now().Add(h.Timeout); d.Before(deadline) {
+			deadline = d
+		}
+	}
+	limit := time.Until(deadline).Round(time.Millisecond)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		err := h.Fn(ctx)
+		returned()
+		done <- err
+	}()
+
+	start := time.Now()
+	progress := time.NewTicker(progressInterval)
+	defer progress.Stop()
+	for {
+		select {
+		case err := <-done:
+			return err
+		case <-ctx.Done():
+			return fmt.Errorf("%w after %v", ErrTimeout, limit)
+		case <-progress.C:
+			waiting(time.Since(start).Round(time.Second))
+		}
+	}
+}
@@ -1,41 +1,87 @@
 package shutdown
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"slices"
 	"sync"
 	"syscall"
 	"time"
 )
 
+// ExitForced is the exit code of a shutdown forced by a second signal
+const ExitForced = 3
+
 // Manager handles graceful shutdown of the application
 type Manager struct {
+	// Observer receives the shutdown events (a ConsoleObserver on stdout
+	// by default)
+	Observer Observer
+	// Exit ends the process when a second signal forces the shutdown
+	// (os.Exit by default)
+	Exit func(code int)
+
 	stopChan      chan os.Signal
+	received      chan os.Signal
 	shutdownChan  chan bool
+	ctx           context.Context
+	cancel        context.CancelFunc
 	mu            sync.Mutex
 	isRunning     bool
 	shutdownTime  time.Time
 	lastError     error
-	shutdownHooks []func() error
-	shutdownDone  bool
+	shutdownHooks []Hook
+	pending       []string
+	// running are the hooks started but not returned, including ones
+	// abandoned after their timeout
+	running      []string
+	shutdownDone bool
 }
 
 // NewManager creates a new shutdown manager
 func NewManager() *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &Manager{
+		Observer:      NewConsoleObserver(),
+		Exit:          os.Exit,
 		stopChan:      make(chan os.Signal, 1),
+		received:      make(chan os.Signal, 1),
 		shutdownChan:  make(chan bool, 1),
+		ctx:           ctx,
+		cancel:        cancel,
 		isRunning:     true,
-		shutdownHooks: make([]func() error, 0),
+		shutdownHooks: make([]Hook, 0),
 	}
 }
 
-// RegisterHook registers a function to be called during shutdown
-func (m *Manager) RegisterHook(hook func() error) {
+// Context returns a context that is cancelled as soon as a shutdown
+// signal arrives or Stop is called. Pass it to in-flight work so it ends
+// promptly instead of delaying the shutdown hooks.
+func (m *Manager) Context() context.Context {
+	return m.ctx
+}
+
+// Register adds a named hook to be called during shutdown
+func (m *Manager) Register(hook Hook) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if hook.Name == "" {
+		hook.Name = fmt.Sprintf("hook %d", len(m.shutdownHooks)+1)
+	}
 	m.shutdownHooks = append(m.shutdownHooks, hook)
+	m.pending = append(m.pending, hook.Name)
+}
+
+// RegisterHook registers a function to be called during shutdown, in
+// PhaseClose without a timeout of its own
+func (m *Manager) RegisterHook(hook func() error) {
+	m.Register(Hook{
+		Phase: PhaseClose,
+		Fn:    func(context.Context) error { return hook() },
+	})
 }
 
 // Start initializes signal handlers
@@ -44,21 +90,47 @@ func (m *Manager) Start() {
 	m.shutdownTime = time.Now()
 	m.mu.Unlock()
 
-	// Register for SIGINT (Ctrl+C) and SIGTERM
-	signal.Notify(m.stopChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	// Register for SIGINT (Ctrl+C) and SIGTERM; SIGHUP is left to the
+	// application, which reloads its configuration
+	signal.Notify(m.stopChan, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		sig := <-m.stopChan
+		m.Stop()
+		m.received <- sig
+
+		// A second signal means the user does not want to wait for the
+		// hooks
+		sig = <-m.stopChan
+		m.emit(Event{Type: EventForced, Signal: sig, Pending: m.Pending()})
+		m.Exit(ExitForced)
+	}()
 }
 
-// Stop triggers graceful shutdown
-func (m *Manager) Stop() {
+// Notify delivers sig as if the process had received it: the first
+// signal starts the shutdown, the second forces the exit. Start must have
+// been called.
+func (m *Manager) Notify(sig os.Signal) {
+	m.stopChan <- sig
+}
+
+// Pending returns the names of the hooks that have not finished yet,
+// during Shutdown the running ones first. A hook that timed out stays
+// pending until it actually returns.
+func (m *Manager) Pending() []string {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	return append(slices.Clone(m.running), m.pending...)
+}
 
-	if m.shutdownDone {
-		return
-	}
+// Stop triggers graceful shutdown: it cancels the context and marks the
+// manager as no longer running. Shutdown then runs the hooks.
+func (m *Manager) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
 	m.isRunning = false
-	m.shutdownDone = true
+	m.cancel()
 }
 
 // IsRunning returns whether the application is still running
@@ -68,10 +140,10 @@ func (m *Manager) IsRunning() bool {
 	return m.isRunning
 }
 
-// WaitForShutdown waits for shutdown signal
+// WaitForShutdown waits for shutdown signal. The context is already
+// cancelled when it returns.
 func (m *Manager) WaitForShutdown() os.Signal {
-	sig := <-m.stopChan
-	return sig
+	return <-m.received
 }
 
 // Shutdown performs graceful shutdown with cleanup
@@ -84,56 +156,100 @@ func (m *Manager) Shutdown(timeout time.Duration) error {
 
 	m.isRunning = false
 	m.shutdownDone = true
+	m.cancel()
+	m.mu.Unlock()
+
+	// Hooks run one at a time by phase and priority. A hook that fails or
+	// times out is reported and the next one still runs; a hook that timed
+	// out may keep running alongside the later phases. Once the overall
+	// timeout has passed the remaining hooks are skipped.
+	m.mu.Lock()
+	hooks := ordered(m.shutdownHooks)
 	m.mu.Unlock()
 
-	fmt.Println("\n" + string([]byte{61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61}))
-	fmt.Println("🔄 شروع خاتمهٔ نرم برنامه...")
-	fmt.Println(string([]byte{61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61}))
+	m.emit(Event{Type: EventStarted, Hooks: len(hooks)})
 
-	// Run shutdown hooks with timeout
 	shutdownCtx := time.Now()
-	hooksCompleted := make(chan error, len(m.shutdownHooks))
-
-	for _, hook := range m.shutdownHooks {
-		go func(h func() error) {
-			if err := h(); err != nil {
-				hooksCompleted <- err
-			} else {
-				hooksCompleted <- nil
+	deadline := shutdownCtx.Add(timeout)
+	var errs []error
+	for i, h := range hooks {
+		start := time.Now()
+		var err error
+		if start.Before(deadline) {
+			m.begin(h.Name, hooks[i+1:])
+			err = h.run(deadline, func(elapsed time.Duration) {
+				m.emit(Event{Type: EventWaiting, Hook: h.Name, Pending: m.Pending(), Duration: elapsed})
+			}, func() { m.returned(h.Name) })
+		} else {
+			m.setPending(hooks[i+1:])
+			err = fmt.Errorf("%w, not run", ErrTimeout)
+		}
+
+		e := Event{Type: EventHookDone, Hook: h.Name, Err: err, Duration: time.Since(start)}
+		if err != nil {
+			e.Type = EventHookFailed
+			if errors.Is(err, ErrTimeout) {
+				e.Type = EventHookTimeout
 			}
-		}(hook)
+			errs = append(errs, &HookError{Hook: h.Name, Err: err})
+		}
+		m.emit(e)
 	}
 
-	// Wait for all hooks to complete or timeout
-	shutdownTimer := time.NewTimer(timeout)
-	defer shutdownTimer.Stop()
+	m.setPending(nil)
 
-	for i := 0; i < len(m.shutdownHooks); i++ {
-		select {
-		case err := <-hooksCompleted:
-			if err != nil {
-				fmt.Printf("⚠️  خطا در shutdown hook: %v\n", err)
-				m.lastError = err
-			}
-		case <-shutdownTimer.C:
-			fmt.Printf("⏱️  مهلت زمانی خاتمه تمام شد\n")
-			return fmt.Errorf("shutdown timeout exceeded")
-		}
+	err := errors.Join(errs...)
+	if err != nil {
+		m.SetLastError(err)
 	}
 
-	// Calculate shutdown duration
-	shutdownDuration := time.Since(shutdownCtx)
+	m.emit(Event{
+		Type:     EventComplete,
+		Err:      err,
+		Duration: time.Since(shutdownCtx),
+		Hooks:    len(hooks),
+		Failed:   len(errs),
+	})
+	return err
+}
+
+// emit sends e to the observer, stamped with the current time
+func (m *Manager) emit(e Event) {
+	if m.Observer == nil {
+		return
+	}
+	e.Time = time.Now()
+	m.Observer.ShutdownEvent(e)
+}
+
+func (m *Manager) setPending(hooks []Hook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.setPendingLocked(hooks)
+}
 
-	fmt.Println("\n📊 آمار نهایی:")
-	fmt.Printf("   ✓ تمام hooks تکمیل شدند\n")
-	fmt.Printf("   ✓ مدت زمان Shutdown: %v\n", shutdownDuration)
-	fmt.Printf("   ✓ زمان خاتمه: %s\n", time.Now().Format("2006-01-02 15:04:05"))
+func (m *Manager) setPendingLocked(hooks []Hook) {
+	m.pending = m.pending[:0]
+	for _, h := range hooks {
+		m.pending = append(m.pending, h.Name)
+	}
+}
 
-	fmt.Println("\n" + string([]byte{61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61}))
-	fmt.Println("✅ برنامه با موفقیت متوقف شد")
-	fmt.Println(string([]byte{61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61}))
+// begin marks a hook as running and the hooks after it as pending
+func (m *Manager) begin(name string, rest []Hook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.running = append(m.running, name)
+	m.setPendingLocked(rest)
+}
 
-	return m.lastError
+// returned removes a hook that returned from the running ones
+func (m *Manager) returned(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if i := slices.Index(m.running, name); i >= 0 {
+		m.running = slices.Delete(m.running, i, i+1)
+	}
 }
 
 // GetShutdownChan returns the shutdown channel for select statements
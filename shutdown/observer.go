@@ -0,0 +1,142 @@
+package shutdown
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"gold-analyzer/i18n"
+)
+
+// EventType identifies a step of the shutdown
+type EventType string
+
+const (
+	EventStarted     EventType = "started"
+	EventHookDone    EventType = "hook_done"
+	EventHookFailed  EventType = "hook_failed"
+	EventHookTimeout EventType = "hook_timeout"
+	// EventWaiting is sent every second while a hook runs
+	EventWaiting EventType = "waiting"
+	// EventForced is sent when a second signal ends the process
+	EventForced   EventType = "forced"
+	EventComplete EventType = "completed"
+)
+
+// Event is one step of the shutdown. Fields not relevant to the type are
+// zero.
+type Event struct {
+	Type EventType
+	Time time.Time
+	// Hook is the hook of the hook events
+	Hook string
+	// Err is the hook's error, or the joined errors on completion
+	Err error
+	// Pending lists the unfinished hooks of waiting and forced events
+	Pending []string
+	// Signal is the signal that forced the exit
+	Signal os.Signal
+	// Duration is the time spent in the hook so far, or in the whole
+	// shutdown on completion
+	Duration time.Duration
+	// Hooks and Failed count the hooks on start and completion
+	Hooks  int
+	Failed int
+}
+
+// Observer receives shutdown events. It may be called from the signal
+// goroutine as well as from Shutdown.
+type Observer interface {
+	ShutdownEvent(e Event)
+}
+
+// Observers sends every event to each observer in turn
+type Observers []Observer
+
+// ShutdownEvent implements Observer
+func (o Observers) ShutdownEvent(e Event) {
+	for _, obs := range o {
+		obs.ShutdownEvent(e)
+	}
+}
+
+// ConsoleObserver prints the localized shutdown banner and progress to a
+// writer (stdout by default)
+type ConsoleObserver struct {
+	Out io.Writer
+}
+
+// NewConsoleObserver creates a console observer writing to stdout
+func NewConsoleObserver() *ConsoleObserver {
+	return &ConsoleObserver{Out: os.Stdout}
+}
+
+// ShutdownEvent implements Observer
+func (c *ConsoleObserver) ShutdownEvent(e Event) {
+	rule := strings.Repeat("=", 70)
+
+	switch e.Type {
+	case EventStarted:
+		fmt.Fprintln(c.Out, "\n"+rule)
+		fmt.Fprintln(c.Out, i18n.T("shutdown.start"))
+		fmt.Fprintln(c.Out, rule)
+	case EventHookFailed:
+		fmt.Fprintln(c.Out, i18n.T("shutdown.hook_error", e.Hook, e.Err))
+	case EventHookTimeout:
+		fmt.Fprintln(c.Out, i18n.T("shutdown.hook_timeout", e.Hook, e.Err))
+	case EventWaiting:
+		fmt.Fprintln(c.Out, i18n.T("shutdown.waiting", strings.Join(e.Pending, ", "), e.Duration))
+	case EventForced:
+		fmt.Fprintln(c.Out, i18n.T("shutdown.forced", e.Signal, strings.Join(e.Pending, ", ")))
+	case EventComplete:
+		fmt.Fprintln(c.Out, i18n.T("shutdown.stats"))
+		if e.Failed == 0 {
+			fmt.Fprintln(c.Out, i18n.T("shutdown.hooks_done"))
+		} else {
+			fmt.Fprintln(c.Out, i18n.T("shutdown.hooks_failed", e.Failed, e.Hooks))
+		}
+		fmt.Fprintln(c.Out, i18n.T("shutdown.duration", e.Duration))
+		fmt.Fprintln(c.Out, i18n.T("shutdown.time", i18n.Time(e.Time)))
+
+		fmt.Fprintln(c.Out, "\n"+rule)
+		fmt.Fprintln(c.Out, i18n.T("shutdown.done"))
+		fmt.Fprintln(c.Out, rule)
+	}
+}
+
+// LogObserver records shutdown events as structured log entries
+type LogObserver struct {
+	Logger *slog.Logger
+}
+
+// ShutdownEvent implements Observer
+func (l *LogObserver) ShutdownEvent(e Event) {
+	switch e.Type {
+	case EventStarted:
+		l.Logger.Info("shutdown started", "hooks", e.Hooks)
+	case EventHookDone:
+		l.Logger.Debug("shutdown hook done", "hook", e.Hook, "duration", e.Duration)
+	case EventHookFailed:
+		l.Logger.Error("shutdown hook failed", "hook", e.Hook, "error", e.Err)
+	case EventHookTimeout:
+		l.Logger.Error("shutdown hook timed out", "hook", e.Hook, "error", e.Err)
+	case EventWaiting:
+		l.Logger.Info("waiting for shutdown hooks", "pending", e.Pending, "elapsed", e.Duration)
+	case EventForced:
+		// Events emitted by the program rather than a signal have none
+		signal := ""
+		if e.Signal != nil {
+			signal = e.Signal.String()
+		}
+		l.Logger.Warn("shutdown forced", "signal", signal, "pending", e.Pending)
+	case EventComplete:
+		if e.Failed > 0 {
+			l.Logger.Warn("shutdown completed with errors", "duration", e.Duration, "hooks", e.Hooks, "failed", e.Failed, "error", e.Err)
+			return
+		}
+		l.Logger.Info("shutdown completed", "duration", e.Duration, "hooks", e.Hooks)
+	}
+}
@@ -0,0 +1,83 @@
+// Package state saves the runtime state of the watch loop to a file on
+// shutdown and restores it on startup, so a restart does not repeat
+// notifications that were already sent or lose the ones not delivered yet.
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gold-analyzer/alerts"
+	"gold-analyzer/atomicfile"
+	"gold-analyzer/backtest"
+	"gold-analyzer/notify"
+	"gold-analyzer/strategy"
+)
+
+// version of the snapshot layout; files of another version are rejected
+const version = 1
+
+// Snapshot is the runtime state of the watch loop
+type Snapshot struct {
+	Version int       `json:"version"`
+	SavedAt time.Time `json:"saved_at"`
+	// Symbols holds the last analysis of every watched symbol
+	Symbols map[string]Symbol `json:"symbols"`
+	// Alerts holds the state of the alert rules evaluated so far, with
+	// their cooldowns and the candle they last fired on
+	Alerts []alerts.RuleState `json:"alerts,omitempty"`
+	// Pending holds the undelivered notifications when they have no queue
+	// file of their own (NOTIFY_QUEUE_FILE)
+	Pending []notify.Delivery `json:"pending,omitempty"`
+}
+
+// Symbol is the last analysis of a symbol
+type Symbol struct {
+	Signal strategy.Signal `json:"signal"`
+	// CandleTime is the time of the last analyzed candle
+	CandleTime time.Time `json:"candle_time,omitzero"`
+	Price      float64   `json:"price,omitempty"`
+	// Position is the open paper position of the symbol, if any
+	Position *backtest.Trade `json:"position,omitempty"`
+}
+
+// Load reads a snapshot. A missing file is not an error and returns an
+// empty snapshot.
+func Load(path string) (Snapshot, error) {
+	s := Snapshot{Version: version, Symbols: make(map[string]Symbol)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("state file %s: %w", path, err)
+	}
+	if s.Version != version {
+		return Snapshot{Version: version, Symbols: make(map[string]Symbol)},
+			fmt.Errorf("state file %s: unsupported version %d", path, s.Version)
+	}
+	if s.Symbols == nil {
+		s.Symbols = make(map[string]Symbol)
+	}
+	return s, nil
+}
+
+// Save writes a snapshot, stamped with the current time
+func Save(path string, s Snapshot) error {
+	s.Version = version
+	s.SavedAt = time.Now()
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.WriteFile(path, data)
+}
@@ -1,5 +1,13 @@
 package strategy
 
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"gold-analyzer/indicators"
+)
+
 type Signal string
 
 const (
@@ -8,25 +16,87 @@ const (
 	HOLD Signal = "HOLD"
 )
 
+// Params are the RSI thresholds of the strategy
+type Params struct {
+	RSIBuyLower      float64
+	RSIBuyUpper      float64
+	RSISellThreshold float64
+	// MinBars is the history the indicators need, from MinBars; zero only
+	// checks that the last values are warmed up
+	MinBars int
+}
+
+// ErrInsufficientHistory is wrapped by the errors about too few bars
+var ErrInsufficientHistory = errors.New("insufficient history")
+
+// MinBars returns the number of bars the strategy needs before it can
+// signal with the given indicator periods: every indicator must be warmed
+// up on the last bar, and ATR on the bar before too.
+func MinBars(rsiPeriod, macdFast, macdSlow, macdSignal, atrPeriod int) int {
+	last := max(
+		indicators.RSIValidFrom(rsiPeriod),
+		indicators.MACDValidFrom(macdFast, macdSlow, macdSignal),
+		indicators.ATRValidFrom(atrPeriod)+1,
+	)
+	return last + 1
+}
+
+// DefaultParams are the thresholds GoldStrategy has always used
+var DefaultParams = Params{RSIBuyLower: 40, RSIBuyUpper: 55, RSISellThreshold: 65}
+
 func GoldStrategy(
 	rsi, macdHist, atr []float64,
 	price float64,
 ) Signal {
+	return GoldStrategyWith(DefaultParams, rsi, macdHist, atr, price)
+}
+
+// GoldStrategyWith evaluates the strategy with custom thresholds. It
+// holds while the indicators are warming up; use Evaluate to know why.
+func GoldStrategyWith(
+	p Params,
+	rsi, macdHist, atr []float64,
+	price float64,
+) Signal {
+	sig, _ := Evaluate(p, rsi, macdHist, atr, price)
+	return sig
+}
 
-	last := len(rsi) - 1
+// Evaluate returns the signal on the last bar, or HOLD and an error
+// wrapping ErrInsufficientHistory when the history is too short to signal.
+// The indicator slices must be equally long.
+func Evaluate(
+	p Params,
+	rsi, macdHist, atr []float64,
+	price float64,
+) (Signal, error) {
+
+	bars := len(rsi)
+	if len(macdHist) != bars || len(atr) != bars {
+		return HOLD, fmt.Errorf("indicator lengths differ: rsi %d, macd hist %d, atr %d", bars, len(macdHist), len(atr))
+	}
+	need := max(p.MinBars, 2)
+	if bars < need {
+		return HOLD, fmt.Errorf("%w: need %d bars, got %d", ErrInsufficientHistory, need, bars)
+	}
+
+	last := bars - 1
+	if math.IsNaN(rsi[last]) || math.IsNaN(macdHist[last]) || math.IsNaN(atr[last]) || math.IsNaN(atr[last-1]) {
+		return HOLD, fmt.Errorf("%w: indicators are still warming up after %d bars", ErrInsufficientHistory, bars)
+	}
 
 	// Buy Conditions
-	if rsi[last] > 40 && rsi[last] < 55 &&
+	if rsi[last] > p.RSIBuyLower && rsi[last] < p.RSIBuyUpper &&
 		macdHist[last] > 0 &&
 		atr[last] > atr[last-1] {
-		return BUY
+		return BUY, nil
 	}
 
 	// Sell Conditions
-	if rsi[last] > 65 &&
+	if rsi[last] > p.RSISellThreshold &&
 		macdHist[last] < 0 {
-		return SELL
+		return SELL, nil
 	}
 
-	return HOLD
+	return HOLD, nil
 }
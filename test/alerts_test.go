@@ -0,0 +1,214 @@
+package test
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gold-analyzer/alerts"
+	"gold-analyzer/i18n"
+	"gold-analyzer/model"
+)
+
+func candlesFromCloses(closes ...float64) []model.Candle {
+	candles := make([]model.Candle, len(closes))
+	for i, c := range closes {
+		candles[i] = model.Candle{Time: int64(i), Open: c, High: c + 1, Low: c - 1, Close: c}
+	}
+	return candles
+}
+
+func TestLoadRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.json")
+	data := `[{"type":"rsi","condition":"below","value":30,"interval":"1d"}]`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := alerts.LoadRules(path)
+	if err != nil {
+		t.Fatalf("LoadRules returned error: %v", err)
+	}
+	if len(rules) != 1 || rules[0].Period != 14 || rules[0].Name == "" {
+		t.Errorf("Expected defaults to be filled, got %+v", rules)
+	}
+}
+
+func TestLoadRulesRejectsUnknownType(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.json")
+	if err := os.WriteFile(path, []byte(`[{"type":"volume","condition":"above"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := alerts.LoadRules(path); err == nil {
+		t.Error("Expected error for unknown rule type")
+	}
+}
+
+func TestLoadRulesRejectsInvalidCooldown(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.json")
+	data := `[{"name":"r","type":"price","condition":"above","value":1,"cooldown":"soon"}]`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := alerts.LoadRules(path); err == nil || !strings.Contains(err.Error(), `cooldown "soon"`) {
+		t.Errorf("LoadRules error = %v, want an invalid cooldown", err)
+	}
+}
+
+func TestLoadRulesRejectsUnknownInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.json")
+	data := `[{"name":"gold 4h","type":"price","condition":"above","value":1,"interval":"4h"}]`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := alerts.LoadRules(path)
+	if err == nil || !strings.Contains(err.Error(), "gold 4h") || !strings.Contains(err.Error(), `"4h"`) {
+		t.Errorf("LoadRules error = %v, want one naming the rule and its interval", err)
+	}
+}
+
+func TestPriceCrossAboveFiresOnce(t *testing.T) {
+	rules := []alerts.Rule{{Name: "above 2500", Type: alerts.TypePrice, Condition: alerts.CondCrossAbove, Value: 2500}}
+	engine := alerts.NewEngine(rules, "GC=F", "1h")
+	feed := alerts.Feed{Symbol: "GC=F", Interval: "1h"}
+
+	// Already above on startup without crossing: no alert
+	fired, _ := engine.Evaluate(feed, candlesFromCloses(2510, 2520))
+	if len(fired) != 0 {
+		t.Errorf("Expected no alert without a crossing, got %d", len(fired))
+	}
+
+	engine.Evaluate(feed, candlesFromCloses(2520, 2490))
+	fired, _ = engine.Evaluate(feed, candlesFromCloses(2490, 2505))
+	if len(fired) != 1 {
+		t.Fatalf("Expected alert on crossing, got %d", len(fired))
+	}
+
+	// Staying above must not fire again
+	fired, _ = engine.Evaluate(feed, candlesFromCloses(2505, 2510))
+	if len(fired) != 0 {
+		t.Errorf("Expected no repeated alert, got %d", len(fired))
+	}
+}
+
+func TestBelowFiresWhenConditionBecomesTrue(t *testing.T) {
+	rules := []alerts.Rule{{Type: alerts.TypePrice, Condition: alerts.CondBelow, Value: 2000}}
+	engine := alerts.NewEngine(rules, "GC=F", "1h")
+	feed := alerts.Feed{Symbol: "GC=F", Interval: "1h"}
+
+	fired, _ := engine.Evaluate(feed, candlesFromCloses(1990, 1980))
+	if len(fired) != 1 {
+		t.Fatalf("Expected alert when already below, got %d", len(fired))
+	}
+
+	fired, _ = engine.Evaluate(feed, candlesFromCloses(1980, 1970))
+	if len(fired) != 0 {
+		t.Errorf("Expected no repeated alert, got %d", len(fired))
+	}
+}
+
+func TestRuleOnOtherFeedIsSkipped(t *testing.T) {
+	rules := []alerts.Rule{{Type: alerts.TypePrice, Condition: alerts.CondAbove, Value: 0, Interval: "1d"}}
+	engine := alerts.NewEngine(rules, "GC=F", "1h")
+
+	feeds := engine.Feeds()
+	if len(feeds) != 1 || feeds[0].Interval != "1d" {
+		t.Fatalf("Expected single 1d feed, got %+v", feeds)
+	}
+
+	fired, _ := engine.Evaluate(alerts.Feed{Symbol: "GC=F", Interval: "1h"}, candlesFromCloses(1, 2))
+	if len(fired) != 0 {
+		t.Errorf("Expected rule on 1d feed to be skipped, got %d", len(fired))
+	}
+}
+
+func TestRSIRuleShortSeries(t *testing.T) {
+	rules := []alerts.Rule{{Type: alerts.TypeRSI, Condition: alerts.CondBelow, Value: 30, Period: 14}}
+	engine := alerts.NewEngine(rules, "GC=F", "1h")
+
+	_, err := engine.Evaluate(alerts.Feed{Symbol: "GC=F", Interval: "1h"}, candlesFromCloses(1, 2, 3))
+	if err == nil {
+		t.Error("Expected error for insufficient history")
+	}
+}
+
+// tradingCandles returns the candles Yahoo would serve for a range of days
+// ending at end, without weekends
+func tradingCandles(end time.Time, days int, step time.Duration) []model.Candle {
+	var candles []model.Candle
+	for t := end.AddDate(0, 0, -days); !t.After(end); t = t.Add(step) {
+		if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+			continue
+		}
+		p := 2500 + 20*math.Sin(float64(len(candles))/5)
+		candles = append(candles, model.Candle{Time: t.Unix(), Open: p, High: p + 3, Low: p - 3, Close: p})
+	}
+	return candles
+}
+
+func TestShippedAlertsExample(t *testing.T) {
+	rules, err := alerts.LoadRules("../alerts.example.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := alerts.NewEngine(rules, "GC=F", "1h")
+
+	// Monday morning has the fewest recent bars
+	end := time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC)
+	steps := map[string]time.Duration{"1h": time.Hour, "1d": 24 * time.Hour}
+	for _, feed := range engine.Feeds() {
+		var days int
+		rg := engine.Range(feed)
+		if _, err := fmt.Sscanf(rg, "%dd", &days); err != nil {
+			t.Fatalf("%s: unexpected range %q", feed.Interval, rg)
+		}
+
+		candles := tradingCandles(end, days, steps[feed.Interval])
+		if _, err := engine.Evaluate(feed, candles); err != nil {
+			t.Errorf("%s: range %s gives %d bars: %v", feed.Interval, rg, len(candles), err)
+		}
+	}
+}
+
+func TestRuleRange(t *testing.T) {
+	daily := alerts.Rule{Type: alerts.TypeRSI, Period: 14, Interval: "1d"}
+	if got := daily.FetchRange(); got != "36d" {
+		t.Errorf("FetchRange = %s, want 36d", got)
+	}
+	daily.Range = "1y"
+	if got := daily.FetchRange(); got != "1y" {
+		t.Errorf("FetchRange = %s, want the rule's own 1y", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "alerts.json")
+	if err := os.WriteFile(path, []byte(`[{"type":"price","condition":"above","range":"week"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := alerts.LoadRules(path); err == nil {
+		t.Error("Expected error for an invalid range")
+	}
+}
+
+func TestTriggerMessageIsLocalized(t *testing.T) {
+	trigger := alerts.Trigger{
+		Rule:  alerts.Rule{Name: "dip", Type: alerts.TypeRSI, Interval: "1d", Condition: alerts.CondCrossBelow, Value: 30},
+		Value: 28.5,
+	}
+
+	i18n.SetDefault(i18n.New(i18n.EN))
+	defer i18n.SetDefault(i18n.New(i18n.DefaultLang))
+	if got := trigger.Message(); got != "dip: rsi(1d) = 28.50, crossed below 30" {
+		t.Errorf("English message = %q", got)
+	}
+
+	i18n.SetDefault(i18n.New(i18n.FA))
+	if got := trigger.Message(); !strings.Contains(got, "عبور به زیر") {
+		t.Errorf("Persian message = %q", got)
+	}
+}
@@ -0,0 +1,241 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gold-analyzer/analysis"
+	"gold-analyzer/api"
+	"gold-analyzer/config"
+	"gold-analyzer/health"
+	"gold-analyzer/strategy"
+)
+
+func newTestServer() (*api.Server, *analysis.Store) {
+	cfg := config.DefaultConfig()
+	cfg.Symbol = "GC=F"
+	store := analysis.NewStore(10)
+	return api.NewServer(":0", cfg, store), store
+}
+
+func TestAPIUnknownSymbol(t *testing.T) {
+	server, _ := newTestServer()
+
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/symbols/XAU/price", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", rec.Code)
+	}
+}
+
+func TestAPILatestSignal(t *testing.T) {
+	server, store := newTestServer()
+	store.Record(analysis.Result{Symbol: "GC=F", Time: time.Now(), Price: 2500, Signal: strategy.HOLD})
+	store.Record(analysis.Result{Symbol: "GC=F", Time: time.Now(), Price: 2510, Signal: strategy.BUY})
+
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/symbols/GC=F/signal", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected JSON content type, got %q", ct)
+	}
+
+	var body struct {
+		Signal string  `json:"signal"`
+		Price  float64 `json:"price"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if body.Signal != "BUY" || body.Price != 2510 {
+		t.Errorf("Unexpected signal response: %+v", body)
+	}
+}
+
+func TestAPIHistory(t *testing.T) {
+	server, store := newTestServer()
+	store.Record(analysis.Result{Symbol: "GC=F", Signal: strategy.HOLD})
+	store.Record(analysis.Result{Symbol: "GC=F", Signal: strategy.HOLD})
+	store.Record(analysis.Result{Symbol: "GC=F", Signal: strategy.SELL})
+
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/symbols/GC=F/history", nil))
+
+	var history []analysis.SignalChange
+	if err := json.NewDecoder(rec.Body).Decode(&history); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	// Only transitions are kept
+	if len(history) != 2 || history[1].Previous != strategy.HOLD || history[1].Signal != strategy.SELL {
+		t.Errorf("Unexpected history: %+v", history)
+	}
+}
+
+func TestAPIStream(t *testing.T) {
+	server, store := newTestServer()
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/stream?symbol=GC=F")
+	if err != nil {
+		t.Fatalf("Stream request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected event stream, got %q", ct)
+	}
+
+	// Give the handler time to subscribe before publishing
+	time.Sleep(50 * time.Millisecond)
+	store.Record(analysis.Result{Symbol: "XAU", Signal: strategy.HOLD})
+	store.Record(analysis.Result{Symbol: "GC=F", Price: 2500, Signal: strategy.BUY})
+
+	var events []string
+	reader := bufio.NewReader(resp.Body)
+	for len(events) < 2 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Reading stream failed: %v", err)
+		}
+		if strings.HasPrefix(line, "event: ") {
+			events = append(events, strings.TrimSpace(strings.TrimPrefix(line, "event: ")))
+		}
+		if strings.HasPrefix(line, "data: ") && strings.Contains(line, "XAU") {
+			t.Error("Expected events for other symbols to be filtered")
+		}
+	}
+
+	if events[0] != "analysis" || events[1] != "signal" {
+		t.Errorf("Unexpected events: %v", events)
+	}
+}
+
+func TestStoreSubscribeLatest(t *testing.T) {
+	store := analysis.NewStore(10)
+	store.Record(analysis.Result{Symbol: "SI=F", Signal: strategy.HOLD})
+	store.Record(analysis.Result{Symbol: "GC=F", Signal: strategy.BUY})
+
+	latest, events, unsubscribe := store.SubscribeLatest(4)
+	defer unsubscribe()
+	if len(latest) != 2 || latest[0].Symbol != "GC=F" || latest[1].Symbol != "SI=F" {
+		t.Fatalf("Unexpected latest results: %+v", latest)
+	}
+
+	// Results recorded before the subscription are not sent again
+	store.Record(analysis.Result{Symbol: "GC=F", Signal: strategy.BUY, Price: 2501})
+	if e := <-events; e.Type != analysis.EventAnalysis || e.Result.Price != 2501 {
+		t.Errorf("Unexpected first event: %+v", e)
+	}
+	select {
+	case e := <-events:
+		t.Errorf("Unexpected event: %+v", e)
+	default:
+	}
+}
+
+type fakeRunner struct{ running bool }
+
+func (f fakeRunner) IsRunning() bool { return f.running }
+
+func TestReadyzReflectsFetchAndShutdown(t *testing.T) {
+	server, _ := newTestServer()
+	tracker := health.NewTracker()
+	runner := &fakeRunner{running: true}
+	server.SetHealth(tracker, runner)
+
+	get := func(path string) int {
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	// No data yet: alive but not ready
+	if code := get("/healthz"); code != http.StatusOK {
+		t.Errorf("Expected /healthz 200 on startup, got %d", code)
+	}
+	if code := get("/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected /readyz 503 without data, got %d", code)
+	}
+
+	tracker.RecordFetch("GC=F", nil)
+	if code := get("/readyz"); code != http.StatusOK {
+		t.Errorf("Expected /readyz 200 after successful fetch, got %d", code)
+	}
+
+	tracker.RecordFetch("GC=F", errors.New("429"))
+	if code := get("/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected /readyz 503 after failed fetch, got %d", code)
+	}
+
+	tracker.RecordFetch("GC=F", nil)
+	runner.running = false
+	if code := get("/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected /readyz 503 during shutdown, got %d", code)
+	}
+}
+
+func TestReadyzReportsWorstSymbol(t *testing.T) {
+	server, _ := newTestServer()
+	tracker := health.NewTracker()
+	server.SetHealth(tracker, &fakeRunner{running: true})
+
+	readyz := func() (int, string) {
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		return rec.Code, rec.Body.String()
+	}
+
+	tracker.RecordFetch("SI=F", errors.New("429"))
+	tracker.RecordFetch("GC=F", nil)
+	code, body := readyz()
+	if code != http.StatusServiceUnavailable || !strings.Contains(body, `"fetch":{"symbol":"SI=F"`) {
+		t.Errorf("Expected the failing SI=F to fail /readyz, got %d %s", code, body)
+	}
+
+	// A symbol removed by a reload no longer counts
+	tracker.Retain("GC=F")
+	if code, body := readyz(); code != http.StatusOK {
+		t.Errorf("Expected /readyz 200 after SI=F was removed, got %d %s", code, body)
+	}
+}
+
+func TestReadyzDrainsBeforeShutdown(t *testing.T) {
+	server, _ := newTestServer()
+	tracker := health.NewTracker()
+	tracker.RecordFetch("GC=F", nil)
+	server.SetHealth(tracker, &fakeRunner{running: true})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	server.Drain(ctx, time.Minute)
+
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "shutting_down") {
+		t.Errorf("Expected /readyz to report shutting_down while draining, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHealthStatusLive(t *testing.T) {
+	now := time.Now()
+	st := health.Status{Started: now.Add(-10 * time.Minute), LastAttempt: now.Add(-5 * time.Minute)}
+
+	if st.Live(now, time.Minute) {
+		t.Error("Expected loop without recent attempts to be stuck")
+	}
+	if !st.Live(now, 10*time.Minute) {
+		t.Error("Expected loop to be live within the allowed idle time")
+	}
+}
@@ -0,0 +1,74 @@
+package test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"gold-analyzer/analysis"
+	"gold-analyzer/backtest"
+	"gold-analyzer/config"
+	"gold-analyzer/strategy"
+)
+
+func TestBacktestPaperTrades(t *testing.T) {
+	start := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	closes := []float64{100, 100, 110, 99, 90, 100, 120}
+	sigs := []strategy.Signal{"", strategy.BUY, strategy.BUY, strategy.HOLD, strategy.SELL, strategy.BUY, strategy.HOLD}
+
+	s := analysis.Series{Closes: closes, Signals: sigs, WarmUp: 1}
+	for i := range closes {
+		s.Times = append(s.Times, start.Add(time.Duration(i)*time.Hour))
+	}
+
+	res, err := backtest.Run(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.Trades) != 2 {
+		t.Fatalf("Expected a closed and an open trade, got %+v", res.Trades)
+	}
+	closed, open := res.Trades[0], res.Trades[1]
+	if closed.EntryPrice != 100 || closed.ExitPrice != 90 || math.Abs(closed.Return+0.1) > 1e-9 {
+		t.Errorf("Unexpected closed trade: %+v", closed)
+	}
+	if !open.Open || open.EntryPrice != 100 || math.Abs(open.Return-0.2) > 1e-9 {
+		t.Errorf("Unexpected open trade: %+v", open)
+	}
+
+	// 0.9 after the loss, then +20% on the open position
+	if math.Abs(res.TotalReturn-0.08) > 1e-9 {
+		t.Errorf("Expected total return 8%%, got %.4f", res.TotalReturn)
+	}
+	if math.Abs(res.BuyAndHold-0.2) > 1e-9 {
+		t.Errorf("Expected buy and hold 20%%, got %.4f", res.BuyAndHold)
+	}
+	if res.WinRate != 0 {
+		t.Errorf("Expected win rate 0, got %.2f", res.WinRate)
+	}
+	// Peak 1.10 at 110, trough 0.90 at 90
+	if math.Abs(res.MaxDrawdown-(1-0.9/1.1)) > 1e-9 {
+		t.Errorf("Unexpected max drawdown %.4f", res.MaxDrawdown)
+	}
+}
+
+func TestBacktestOnSeries(t *testing.T) {
+	cfg := config.DefaultConfig()
+	s, err := analysis.BuildSeries(context.Background(), cfg, waveCandles(300))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := backtest.Run(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Bars != len(s.Closes)-s.WarmUp {
+		t.Errorf("Expected %d bars, got %d", len(s.Closes)-s.WarmUp, res.Bars)
+	}
+	if res.MaxDrawdown < 0 || res.MaxDrawdown > 1 {
+		t.Errorf("Drawdown out of range: %f", res.MaxDrawdown)
+	}
+}
@@ -0,0 +1,328 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gold-analyzer/config"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigLoadPrecedence(t *testing.T) {
+	path := writeConfig(t, `{
+		"interval": "1d",
+		"rsi_period": 10,
+		"check_interval": "5m",
+		"enable_notifications": true
+	}`)
+	t.Setenv("RSI_PERIOD", "20")
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if cfg.Interval != "1d" || cfg.CheckInterval != 5*time.Minute || !cfg.EnableNotifications {
+		t.Errorf("file values not applied: %+v", cfg)
+	}
+	if cfg.RSIPeriod != 20 {
+		t.Errorf("RSIPeriod = %d, want 20 from the environment", cfg.RSIPeriod)
+	}
+
+	sources := map[string]string{
+		"symbol":         "default",
+		"interval":       "file " + path,
+		"check_interval": "file " + path,
+		"rsi_period":     "env RSI_PERIOD",
+	}
+	for key, want := range sources {
+		if got := cfg.Source(key); got != want {
+			t.Errorf("Source(%s) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestConfigLoadRejectsUnknownKeys(t *testing.T) {
+	path := writeConfig(t, `{"rsi_perod": 10}`)
+	if _, err := config.Load(path); err == nil || !strings.Contains(err.Error(), "rsi_perod") {
+		t.Errorf("Load error = %v, want unknown field rsi_perod", err)
+	}
+
+	path = writeConfig(t, `{"check_interval": 5}`)
+	if _, err := config.Load(path); err == nil {
+		t.Error("numeric duration was accepted")
+	}
+}
+
+func TestConfigTargets(t *testing.T) {
+	path := writeConfig(t, `{
+		"rsi_period": 14,
+		"watchlist": [
+			{"symbol": "GC=F"},
+			{"symbol": "SI=F", "interval": "4h", "check_interval": "15m", "strategy": {"rsi_period": 10, "rsi_sell_threshold": 70}}
+		]
+	}`)
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	targets := cfg.Targets()
+	if len(targets) != 2 {
+		t.Fatalf("got %d targets, want 2", len(targets))
+	}
+	gold, silver := targets[0], targets[1]
+	if gold.Symbol != "GC=F" || gold.RSIPeriod != 14 || gold.Interval != cfg.Interval {
+		t.Errorf("GC=F should inherit the global settings: %+v", gold)
+	}
+	if silver.Symbol != "SI=F" || silver.Interval != "4h" || silver.CheckInterval != 15*time.Minute ||
+		silver.RSIPeriod != 10 || silver.RSISellThreshold != 70 || silver.RSIBuyLower != cfg.RSIBuyLower {
+		t.Errorf("SI=F overrides not applied: %+v", silver)
+	}
+
+	// A flag outranks the file: -interval applies to every symbol and
+	// -symbol replaces the watchlist but keeps the entry's overrides
+	cfg.Interval = "1d"
+	cfg.Symbol = "SI=F"
+	cfg.SetSource(config.SourceFlag, "interval", "symbol")
+
+	targets = cfg.Targets()
+	if len(targets) != 1 {
+		t.Fatalf("got %d targets, want 1", len(targets))
+	}
+	if got := targets[0]; got.Interval != "1d" || got.RSIPeriod != 10 {
+		t.Errorf("flag target = %+v, want interval 1d and RSI period 10", got)
+	}
+}
+
+func TestConfigWatchlistEnv(t *testing.T) {
+	t.Setenv("WATCHLIST", "GC=F, SI=F,")
+
+	cfg := config.DefaultConfig()
+	targets := cfg.Targets()
+	if len(targets) != 2 || targets[0].Symbol != "GC=F" || targets[1].Symbol != "SI=F" {
+		t.Errorf("targets = %v, want GC=F and SI=F", targets)
+	}
+	if got := cfg.Source("watchlist"); got != "env WATCHLIST" {
+		t.Errorf("Source(watchlist) = %q", got)
+	}
+}
+
+func TestConfigSourceIsThePresentLayer(t *testing.T) {
+	path := writeConfig(t, `{"rsi_period": 14, "watchlist": [{"symbol": "SI=F"}, {"symbol": "PL=F"}]}`)
+	t.Setenv("SYMBOL", "GC=F")
+	t.Setenv("INTERVAL", "")
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	// Values equal to the defaults still come from the layer that set them
+	for key, want := range map[string]string{
+		"symbol":     "env SYMBOL",
+		"rsi_period": "file " + path,
+		"interval":   config.SourceDefault,
+	} {
+		if got := cfg.Source(key); got != want {
+			t.Errorf("Source(%s) = %q, want %q", key, got, want)
+		}
+	}
+
+	// The environment outranks the file watchlist whatever the symbol
+	if targets := cfg.Targets(); len(targets) != 1 || targets[0].Symbol != "GC=F" {
+		t.Errorf("targets = %v, want GC=F only", targets)
+	}
+}
+
+func TestShippedConfigExamples(t *testing.T) {
+	cfg, err := config.Load("../config.example.json")
+	if err != nil {
+		t.Fatalf("config.example.json: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("config.example.json: %v", err)
+	}
+
+	// The YAML and TOML examples hold the same settings
+	for _, name := range []string{"config.example.yaml", "config.example.toml"} {
+		other, err := config.Load("../" + name)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		want, got := cfg.Targets(), other.Targets()
+		if len(got) != len(want) {
+			t.Errorf("%s: %d targets, want %d", name, len(got), len(want))
+			continue
+		}
+		for i := range want {
+			if diff := config.Diff(want[i], got[i]); len(diff) > 0 {
+				t.Errorf("%s: %s differs from config.example.json: %+v", name, want[i].Symbol, diff)
+			}
+		}
+	}
+
+	data, err := os.ReadFile("../.env.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if key, value, ok := strings.Cut(line, "="); ok && !strings.HasPrefix(line, "#") {
+			t.Setenv(key, value)
+		}
+	}
+	if err := config.DefaultConfig().Validate(); err != nil {
+		t.Errorf(".env.example: %v", err)
+	}
+}
+
+func TestConfigValidateDefaults(t *testing.T) {
+	if err := config.DefaultConfig().Validate(); err != nil {
+		t.Errorf("default configuration is invalid: %v", err)
+	}
+}
+
+func TestConfigValidateReportsEveryProblem(t *testing.T) {
+	t.Setenv("RSI_PERIOD", "0")
+	t.Setenv("RSI_BUY_LOWER", "60")
+	t.Setenv("CHECK_INTERVAL_MINUTES", "soon")
+	t.Setenv("LOG_COMPRESS", "yes")
+	t.Setenv("INTERVAL", "1m")
+	t.Setenv("RANGE", "1mo")
+
+	err := config.DefaultConfig().Validate()
+	if err == nil {
+		t.Fatal("Validate accepted an invalid configuration")
+	}
+
+	for _, want := range []string{
+		`CHECK_INTERVAL_MINUTES="soon"`,
+		`LOG_COMPRESS="yes"`,
+		"rsi_period = 0 (env RSI_PERIOD)",
+		"rsi_buy_lower = 60 (env RSI_BUY_LOWER): must be less than rsi_buy_upper",
+		"range = 1mo (env RANGE): 1m candles are only available for the last 7 days",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestConfigValidateWatchlistOverrides(t *testing.T) {
+	path := writeConfig(t, `{
+		"watchlist": [
+			{"symbol": "GC=F"},
+			{"symbol": "SI=F", "strategy": {"macd_fast_period": 30}},
+			{"symbol": "GC=F"}
+		]
+	}`)
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	err = cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate accepted an invalid watchlist")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "watchlist SI=F: macd_fast_period = 30") {
+		t.Errorf("override problem not reported:\n%s", msg)
+	}
+	if !strings.Contains(msg, "GC=F is listed twice") {
+		t.Errorf("duplicate symbol not reported:\n%s", msg)
+	}
+	if n := strings.Count(msg, "\n") + 1; n != 2 {
+		t.Errorf("got %d problems, want 2:\n%s", n, msg)
+	}
+}
+
+func TestConfigDiff(t *testing.T) {
+	old := config.DefaultConfig()
+	path := writeConfig(t, `{"rsi_period": 10, "watchlist": [{"symbol": "SI=F"}]}`)
+	next, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	changes := config.Diff(old, next)
+	if len(changes) != 2 {
+		t.Fatalf("got %d changes, want 2: %+v", len(changes), changes)
+	}
+	if c := changes[0]; c.Key != "watchlist" || c.Old != "" || c.New != `[{"symbol":"SI=F"}]` {
+		t.Errorf("changes[0] = %+v", c)
+	}
+	if c := changes[1]; c.Key != "rsi_period" || c.Old != "14" || c.New != "10" {
+		t.Errorf("changes[1] = %+v", c)
+	}
+	if got := config.Diff(next, next); len(got) != 0 {
+		t.Errorf("Diff of identical configs = %+v", got)
+	}
+}
+
+func TestConfigFileFormats(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.yml": `# comment
+symbol: "SI=F"   # quoted
+rsi_period: 10
+watchlist:
+- symbol: SI=F
+  strategy:
+    rsi_buy_lower: 35.5
+`,
+		"a.toml": `symbol = 'SI=F' # literal
+rsi_period = 1_0
+
+[[watchlist]]
+symbol = "SI=F"
+
+[watchlist.strategy]
+rsi_buy_lower = 35.5
+`,
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := config.Load(path)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if cfg.Symbol != "SI=F" || cfg.RSIPeriod != 10 || cfg.Source("rsi_period") != "file "+path {
+			t.Errorf("%s: symbol %s, rsi_period %d from %s", name, cfg.Symbol, cfg.RSIPeriod, cfg.Source("rsi_period"))
+		}
+		if targets := cfg.Targets(); len(targets) != 1 || targets[0].RSIBuyLower != 35.5 {
+			t.Errorf("%s: watchlist override not applied: %+v", name, cfg.Watchlist)
+		}
+	}
+
+	for name, data := range map[string]string{
+		"bad.yaml":  "symbol: GC=F\n  interval: 1h\n",
+		"bad.toml":  "symbol = \"GC=F\"\ninterval = 1h\n",
+		"typo.yaml": "rsi_perod: 14\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := config.Load(path); err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("%s: Load error = %v, want one naming the file", name, err)
+		}
+	}
+}
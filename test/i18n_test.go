@@ -0,0 +1,79 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"gold-analyzer/i18n"
+)
+
+func TestResolveLanguage(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+
+	t.Setenv("LANG", "en_US.UTF-8")
+	if lang := i18n.Resolve(""); lang != i18n.EN {
+		t.Errorf("Expected en from LANG, got %s", lang)
+	}
+	if lang := i18n.Resolve("fa"); lang != i18n.FA {
+		t.Errorf("Expected configured language to win, got %s", lang)
+	}
+
+	t.Setenv("LANG", "C")
+	if lang := i18n.Resolve(""); lang != i18n.DefaultLang {
+		t.Errorf("Expected default language for C locale, got %s", lang)
+	}
+}
+
+func TestPrinterEnglish(t *testing.T) {
+	p := i18n.New("en_GB")
+	if p.Lang() != i18n.EN || p.RTL() {
+		t.Fatalf("Expected LTR English printer, got %s", p.Lang())
+	}
+
+	got := p.T("analysis.change", "↑", 1.5, 0.25)
+	if got != "   ↑ Change: 1.50 USD (0.25%)" {
+		t.Errorf("Unexpected message: %q", got)
+	}
+}
+
+func TestPrinterPersianIsolatesValues(t *testing.T) {
+	p := i18n.New("fa_IR.UTF-8")
+	if !p.RTL() || !p.Isolate {
+		t.Fatal("Expected Persian printer to be RTL with isolation")
+	}
+
+	got := p.T("analysis.price", 2500.0)
+	if !strings.Contains(got, "\u20662500.00\u2069") {
+		t.Errorf("Expected price wrapped in LTR isolate, got %q", got)
+	}
+
+	p.Isolate = false
+	if got := p.T("analysis.price", 2500.0); strings.Contains(got, "\u2066") {
+		t.Errorf("Expected no isolates when disabled, got %q", got)
+	}
+}
+
+func TestPrinterUnknownKey(t *testing.T) {
+	p := i18n.New("fa")
+	if got := p.T("missing.key"); got != "missing.key" {
+		t.Errorf("Expected key as fallback, got %q", got)
+	}
+}
+
+func TestCatalogsMatch(t *testing.T) {
+	fa := i18n.New(i18n.FA)
+	fa.Isolate = false
+	en := i18n.New(i18n.EN)
+
+	// Every message must exist in both catalogs with the same format verbs
+	for _, key := range i18n.Keys(i18n.EN) {
+		faMsg, enMsg := fa.T(key), en.T(key)
+		if strings.Count(faMsg, "%") != strings.Count(enMsg, "%") {
+			t.Errorf("Key %s has different format verbs: %q vs %q", key, faMsg, enMsg)
+		}
+	}
+	if len(i18n.Keys(i18n.FA)) != len(i18n.Keys(i18n.EN)) {
+		t.Errorf("Catalog sizes differ: fa=%d en=%d", len(i18n.Keys(i18n.FA)), len(i18n.Keys(i18n.EN)))
+	}
+}
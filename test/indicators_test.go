@@ -1,6 +1,9 @@
 package test
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"testing"
 
 	"gold-analyzer/indicators"
@@ -14,7 +17,10 @@ func TestRSI(t *testing.T) {
 		46.00, 46.00, 46.00, 46.00, 46.00,
 	}
 
-	rsi := indicators.RSI(closes, 14)
+	rsi, err := indicators.RSI(closes, 14)
+	if err != nil {
+		t.Fatalf("RSI: %v", err)
+	}
 
 	// بررسی طول نتیجه
 	if len(rsi) != len(closes) {
@@ -51,7 +57,10 @@ func TestATR(t *testing.T) {
 		50, 50.2, 50.5, 50.8, 51,
 	}
 
-	atr := indicators.ATR(high, low, close, 14)
+	atr, err := indicators.ATR(high, low, close, 14)
+	if err != nil {
+		t.Fatalf("ATR: %v", err)
+	}
 
 	// بررسی طول نتیجه
 	if len(atr) != len(close) {
@@ -79,7 +88,10 @@ func TestMACD(t *testing.T) {
 		47.14, 47.54, 48.20, 48.26, 48.38, 49.00, 49.14, 49.40, 49.63, 50.10,
 	}
 
-	macd, signal, hist := indicators.MACD(closes)
+	macd, signal, hist, err := indicators.MACD(closes)
+	if err != nil {
+		t.Fatalf("MACD: %v", err)
+	}
 
 	// بررسی طول
 	if len(macd) != len(closes) {
@@ -101,6 +113,250 @@ func TestMACD(t *testing.T) {
 	}
 }
 
+func TestIndicatorWarmUpIsNaN(t *testing.T) {
+	n := 40
+	closes := make([]float64, n)
+	high := make([]float64, n)
+	low := make([]float64, n)
+	for i := range closes {
+		closes[i] = 100 + float64(i%7) - float64(i%3)
+		high[i] = closes[i] + 1
+		low[i] = closes[i] - 1
+	}
+
+	rsi, _ := indicators.RSI(closes, 14)
+	atr, _ := indicators.ATR(high, low, closes, 14)
+	ema, _ := indicators.EMA(closes, 10)
+	_, _, hist, _ := indicators.MACDPeriods(closes, 8, 21, 5)
+	for _, c := range []struct {
+		name   string
+		values []float64
+		from   int
+	}{
+		{"RSI", rsi, indicators.RSIValidFrom(14)},
+		{"ATR", atr, indicators.ATRValidFrom(14)},
+		{"EMA", ema, indicators.EMAValidFrom(10)},
+		{"MACD histogram", hist, indicators.MACDValidFrom(8, 21, 5)},
+	} {
+		if len(c.values) != n {
+			t.Fatalf("%s has %d values, want %d", c.name, len(c.values), n)
+		}
+		for i, v := range c.values {
+			if warm := i < c.from; warm != math.IsNaN(v) {
+				t.Errorf("%s[%d] = %v, want NaN only before index %d", c.name, i, v, c.from)
+			}
+		}
+	}
+}
+
+func TestEMASeededWithAverage(t *testing.T) {
+	ema, err := indicators.EMA([]float64{1, 2, 3, 4, 5}, 3)
+	if err != nil {
+		t.Fatalf("EMA: %v", err)
+	}
+	if !math.IsNaN(ema[0]) || !math.IsNaN(ema[1]) {
+		t.Errorf("warm-up values = %v, want NaN", ema[:2])
+	}
+	for i, want := range map[int]float64{2: 2, 3: 3, 4: 4} {
+		if ema[i] != want {
+			t.Errorf("EMA[%d] = %v, want %v", i, ema[i], want)
+		}
+	}
+}
+
+func TestIndicatorInputErrors(t *testing.T) {
+	short := []float64{1, 2, 3}
+	long := make([]float64, 30)
+
+	for _, c := range []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"RSI short", second(indicators.RSI(short, 14)), indicators.ErrTooShort},
+		{"RSI period 0", second(indicators.RSI(long, 0)), indicators.ErrPeriod},
+		{"EMA period -1", second(indicators.EMA(long, -1)), indicators.ErrPeriod},
+		{"EMA short", second(indicators.EMA(short, 5)), indicators.ErrTooShort},
+		{"ATR short", second(indicators.ATR(short, short, short, 14)), indicators.ErrTooShort},
+		{"ATR lengths", second(indicators.ATR(long, short, long, 14)), indicators.ErrLengthMismatch},
+		{"MACD short", fourth(indicators.MACD(long[:20])), indicators.ErrTooShort},
+	} {
+		if !errors.Is(c.err, c.want) {
+			t.Errorf("%s: error = %v, want %v", c.name, c.err, c.want)
+		}
+	}
+
+	_, err := indicators.RSI(short, 14)
+	if err == nil || err.Error() != "RSI(14): not enough values: need 15, got 3" {
+		t.Errorf("RSI error = %v", err)
+	}
+}
+
+func second(_ []float64, err error) error { return err }
+
+func fourth(_, _, _ []float64, err error) error { return err }
+
+// fuzzSeries decodes bytes into a price-like series; each byte moves the
+// price by up to ±12.8
+func fuzzSeries(data []byte) []float64 {
+	series := make([]float64, len(data))
+	price := 100.0
+	for i, b := range data {
+		price += float64(int8(b)) / 10
+		series[i] = price
+	}
+	return series
+}
+
+func FuzzIndicators(f *testing.F) {
+	f.Add([]byte("gold went up and then down again, twice over"), []byte{3, 1, 4, 1, 5}, 14)
+	f.Add([]byte{}, []byte{}, 0)
+	f.Add([]byte{1, 2}, []byte{1, 2, 3}, 1)
+	f.Add(make([]byte, 64), make([]byte, 64), -3)
+
+	f.Fuzz(func(t *testing.T, data, spread []byte, period int) {
+		closes := fuzzSeries(data)
+		high := make([]float64, len(closes))
+		low := make([]float64, len(closes))
+		for i, c := range closes {
+			var s float64
+			if len(spread) > 0 {
+				s = float64(spread[i%len(spread)]) / 50
+			}
+			high[i], low[i] = c+s, c-s
+		}
+
+		// Every indicator either fails or returns one value per input
+		check := func(name string, values []float64, err error, from int) {
+			if err != nil {
+				return
+			}
+			if len(values) != len(closes) {
+				t.Fatalf("%s returned %d values for %d inputs", name, len(values), len(closes))
+			}
+			for i, v := range values {
+				if warm := i < from; warm != math.IsNaN(v) {
+					t.Fatalf("%s[%d] = %v, want NaN only before %d", name, i, v, from)
+				}
+			}
+		}
+
+		rsi, err := indicators.RSI(closes, period)
+		check("RSI", rsi, err, indicators.RSIValidFrom(period))
+		for _, v := range rsi {
+			if v < 0 || v > 100 {
+				t.Fatalf("RSI out of range: %v", v)
+			}
+		}
+
+		atr, err := indicators.ATR(high, low, closes, period)
+		check("ATR", atr, err, indicators.ATRValidFrom(period))
+
+		ema, err := indicators.EMA(closes, period)
+		check("EMA", ema, err, indicators.EMAValidFrom(period))
+
+		fast, slow, signal := period, 2*period, max(1, period/2)
+		_, _, hist, err := indicators.MACDPeriods(closes, fast, slow, signal)
+		check("MACD", hist, err, indicators.MACDValidFrom(fast, slow, signal))
+
+		// The streams give the same values wherever the batch succeeds
+		if rsi != nil {
+			stream, _ := indicators.NewRSIStream(period)
+			for i, c := range closes {
+				sameValue(t, "RSI stream", i, stream.Update(c), rsi[i])
+			}
+		}
+		if atr != nil {
+			stream, _ := indicators.NewATRStream(period)
+			for i, c := range closes {
+				sameValue(t, "ATR stream", i, stream.Update(high[i], low[i], c), atr[i])
+			}
+		}
+		if ema != nil {
+			stream, _ := indicators.NewEMAStream(period)
+			for i, c := range closes {
+				sameValue(t, "EMA stream", i, stream.Update(c), ema[i])
+			}
+		}
+		if hist != nil {
+			stream, _ := indicators.NewMACDStream(fast, slow, signal)
+			for i, c := range closes {
+				_, _, h := stream.Update(c)
+				sameValue(t, "MACD stream", i, h, hist[i])
+			}
+		}
+
+		// Mismatched lengths are an error, never a panic
+		if len(closes) > 0 {
+			if _, err := indicators.ATR(high[1:], low, closes, period); err == nil {
+				t.Fatal("ATR accepted a shorter high slice")
+			}
+		}
+	})
+}
+
+func TestStreamsMatchBatch(t *testing.T) {
+	candles := waveCandles(300)
+	closes := make([]float64, len(candles))
+	highs := make([]float64, len(candles))
+	lows := make([]float64, len(candles))
+	for i, c := range candles {
+		closes[i], highs[i], lows[i] = c.Close, c.High, c.Low
+	}
+
+	for _, period := range []int{1, 2, 14, 50} {
+		rsi, _ := indicators.RSI(closes, period)
+		rsiStream, _ := indicators.NewRSIStream(period)
+		atr, _ := indicators.ATR(highs, lows, closes, period)
+		atrStream, _ := indicators.NewATRStream(period)
+		ema, _ := indicators.EMA(closes, period)
+		emaStream, _ := indicators.NewEMAStream(period)
+		for i, c := range candles {
+			sameValue(t, fmt.Sprintf("RSI(%d)", period), i, rsiStream.Update(c.Close), rsi[i])
+			sameValue(t, fmt.Sprintf("ATR(%d)", period), i, atrStream.Update(c.High, c.Low, c.Close), atr[i])
+			sameValue(t, fmt.Sprintf("EMA(%d)", period), i, emaStream.Update(c.Close), ema[i])
+		}
+	}
+
+	// A fast period slower than the slow one must not change the warm-up
+	for _, p := range [][3]int{{8, 21, 5}, {12, 26, 9}, {26, 12, 9}, {1, 1, 1}} {
+		macd, signal, hist, _ := indicators.MACDPeriods(closes, p[0], p[1], p[2])
+		stream, _ := indicators.NewMACDStream(p[0], p[1], p[2])
+		name := fmt.Sprintf("MACD%v", p)
+		for i, c := range closes {
+			m, s, h := stream.Update(c)
+			sameValue(t, name+" line", i, m, macd[i])
+			sameValue(t, name+" signal", i, s, signal[i])
+			sameValue(t, name+" hist", i, h, hist[i])
+		}
+	}
+}
+
+func TestStreamCopyIsIndependent(t *testing.T) {
+	stream, _ := indicators.NewRSIStream(3)
+	for _, c := range []float64{10, 11, 10, 12, 13} {
+		stream.Update(c)
+	}
+
+	// Evaluating the unfinished candle on a copy leaves the stream alone
+	tick := *stream
+	tick.Update(20)
+	want, _ := indicators.RSI([]float64{10, 11, 10, 12, 13, 12}, 3)
+	sameValue(t, "RSI stream", 5, stream.Update(12), want[5])
+
+	if _, err := indicators.NewMACDStream(12, 0, 9); !errors.Is(err, indicators.ErrPeriod) {
+		t.Errorf("MACD stream error = %v, want %v", err, indicators.ErrPeriod)
+	}
+}
+
+// sameValue compares bit for bit, NaN included
+func sameValue(t *testing.T, name string, i int, got, want float64) {
+	t.Helper()
+	if math.Float64bits(got) != math.Float64bits(want) && !(math.IsNaN(got) && math.IsNaN(want)) {
+		t.Fatalf("%s[%d] = %v, batch %v", name, i, got, want)
+	}
+}
+
 func BenchmarkRSI(b *testing.B) {
 	closes := make([]float64, 1000)
 	for i := 0; i < 1000; i++ {
@@ -141,3 +397,12 @@ func BenchmarkMACD(b *testing.B) {
 		indicators.MACD(closes)
 	}
 }
+
+func BenchmarkRSIStream(b *testing.B) {
+	stream, _ := indicators.NewRSIStream(14)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stream.Update(float64(i%100) + 40)
+	}
+}
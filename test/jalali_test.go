@@ -0,0 +1,144 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gold-analyzer/config"
+	"gold-analyzer/i18n"
+	"gold-analyzer/jalali"
+	"gold-analyzer/logging"
+)
+
+func TestJalaliFromGregorian(t *testing.T) {
+	tests := []struct {
+		year  int
+		month time.Month
+		day   int
+		want  string
+	}{
+		{1979, time.February, 11, "1357/11/22"},
+		{2024, time.March, 20, "1403/01/01"},
+		{2025, time.March, 20, "1403/12/30"},
+		{2025, time.March, 21, "1404/01/01"},
+		{2025, time.September, 22, "1404/06/31"},
+		{2025, time.September, 23, "1404/07/01"},
+	}
+
+	for _, tt := range tests {
+		if got := jalali.FromGregorian(tt.year, tt.month, tt.day).String(); got != tt.want {
+			t.Errorf("FromGregorian(%d-%02d-%02d) = %s, want %s", tt.year, tt.month, tt.day, got, tt.want)
+		}
+	}
+}
+
+func TestJalaliRoundTrip(t *testing.T) {
+	day := time.Date(1990, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 365*50; i++ {
+		d := day.AddDate(0, 0, i)
+		j := jalali.FromTime(d)
+		y, m, dd := jalali.ToGregorian(j)
+		if y != d.Year() || m != d.Month() || dd != d.Day() {
+			t.Fatalf("Round trip of %s via %s gave %d-%02d-%02d", d.Format("2006-01-02"), j, y, m, dd)
+		}
+	}
+
+	if !jalali.IsLeap(1403) || jalali.IsLeap(1404) {
+		t.Error("Expected 1403 to be a leap year and 1404 not")
+	}
+}
+
+func TestPrinterTimeCalendars(t *testing.T) {
+	tehran, err := i18n.LoadLocation("", i18n.Jalali)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 21:00 UTC is already the next day in Tehran
+	ts := time.Date(2025, time.March, 20, 21, 0, 0, 0, time.UTC)
+
+	p := i18n.New(i18n.EN)
+	p.Location = tehran
+
+	p.Calendar = i18n.Jalali
+	if got := p.Time(ts); got != "1404/01/01 00:30:00" {
+		t.Errorf("Unexpected Jalali time: %q", got)
+	}
+
+	p.Calendar = i18n.Both
+	if got := p.Time(ts); got != "1404/01/01 00:30:00 (2025-03-21 00:30:00)" {
+		t.Errorf("Unexpected combined time: %q", got)
+	}
+
+	if _, err := i18n.ParseCalendar("lunar"); err == nil {
+		t.Error("Expected error for unknown calendar")
+	}
+}
+
+func TestLoggingJalaliTimestamps(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.LogFile = filepath.Join(t.TempDir(), "signals.log")
+	cfg.LogFormat = "json"
+	cfg.TimeCalendar = "both"
+
+	logger, closer, err := logging.New(cfg)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	logger.Info("signal", "symbol", "GC=F")
+	closer.Close()
+
+	data, err := os.ReadFile(cfg.LogFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatalf("Expected a single JSON record, got %q: %v", data, err)
+	}
+	if _, ok := record["time"].(string); !ok {
+		t.Errorf("Expected Gregorian time to be kept, got %v", record)
+	}
+	if j, _ := record["time_jalali"].(string); !strings.HasPrefix(j, "14") {
+		t.Errorf("Expected Jalali timestamp, got %v", record)
+	}
+
+	cfg.TimeCalendar = "lunar"
+	if _, _, err := logging.New(cfg); err == nil {
+		t.Error("Expected error for unknown calendar")
+	}
+}
+
+func TestLoggingJalaliTimeLeadsOutsideGroups(t *testing.T) {
+	for _, c := range []struct{ calendar, prefix string }{
+		{"jalali", `time="14`},
+		{"both", "time=20"},
+	} {
+		cfg := config.DefaultConfig()
+		cfg.LogFile = filepath.Join(t.TempDir(), "signals.log")
+		cfg.TimeCalendar = c.calendar
+
+		logger, closer, err := logging.New(cfg)
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+		logger.WithGroup("fetch").Info("retry", "attempt", 2)
+		closer.Close()
+
+		data, _ := os.ReadFile(cfg.LogFile)
+		line := string(data)
+		if !strings.HasPrefix(line, c.prefix) {
+			t.Errorf("%s: expected the time first, got %q", c.calendar, line)
+		}
+		if strings.Contains(line, "fetch.time") {
+			t.Errorf("%s: time ended up in the group: %q", c.calendar, line)
+		}
+		if c.calendar == "both" && !strings.Contains(line, ` time_jalali="14`) {
+			t.Errorf("both: expected time_jalali after the time, got %q", line)
+		}
+	}
+}
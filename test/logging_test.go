@@ -0,0 +1,199 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"gold-analyzer/config"
+	"gold-analyzer/logging"
+)
+
+func TestLoggingJSONToFile(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.LogFile = filepath.Join(t.TempDir(), "signals.log")
+	cfg.LogFormat = "json"
+	cfg.LogLevel = "info"
+
+	logger, closer, err := logging.New(cfg)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	logger.Debug("hidden")
+	logger.Info("signal", "symbol", "GC=F", "signal", "BUY", "price", 2500.5)
+	if err := closer.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(cfg.LogFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatalf("Expected a single JSON record, got %q: %v", data, err)
+	}
+	if record["msg"] != "signal" || record["symbol"] != "GC=F" || record["signal"] != "BUY" {
+		t.Errorf("Unexpected record: %v", record)
+	}
+}
+
+func TestLoggingRejectsInvalidSettings(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.LogLevel = "loud"
+	if _, _, err := logging.New(cfg); err == nil {
+		t.Error("Expected error for unknown log level")
+	}
+
+	cfg = config.DefaultConfig()
+	cfg.LogFormat = "xml"
+	if _, _, err := logging.New(cfg); err == nil {
+		t.Error("Expected error for unknown log format")
+	}
+}
+
+func TestRotatingWriterRotatesBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signals.log")
+	w := &logging.RotatingWriter{Path: path, MaxSize: 20, MaxBackups: 2, Compress: true}
+	if err := w.Open(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, err := w.Write([]byte("0123456789abcde\n")); err != nil {
+			t.Fatalf("Write returned error: %v", err)
+		}
+	}
+	if err := w.Sync(); err != nil {
+		t.Fatalf("Sync returned error: %v", err)
+	}
+
+	backups, err := w.Backups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("Expected 2 retained backups, got %v", backups)
+	}
+	for _, b := range backups {
+		if filepath.Ext(b) != ".gz" {
+			t.Errorf("Expected compressed backup, got %s", b)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "0123456789abcde\n" {
+		t.Errorf("Expected only the last line in active file, got %q", data)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if _, err := w.Write([]byte("late")); err == nil {
+		t.Error("Expected write after close to fail")
+	}
+}
+
+func TestRotatingWriterRotatesByAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signals.log")
+	w := &logging.RotatingWriter{Path: path, MaxAge: 10 * time.Millisecond}
+	if err := w.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	w.Write([]byte("first\n"))
+	time.Sleep(20 * time.Millisecond)
+	w.Write([]byte("second\n"))
+	w.Sync()
+
+	backups, _ := w.Backups()
+	if len(backups) != 1 {
+		t.Fatalf("Expected 1 backup after MaxAge, got %v", backups)
+	}
+}
+
+func TestRotatingWriterAgeSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "signals.log")
+	old := time.Now().Add(-2 * time.Hour)
+
+	// A file last rotated two hours ago, written to until just now
+	backup := path + "." + old.Format("20060102-150405.000000000") + ".gz"
+	for _, f := range []string{backup, path, path + ".old", path + ".20250101.gz"} {
+		if err := os.WriteFile(f, []byte("line\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := &logging.RotatingWriter{Path: path, MaxAge: time.Hour}
+	if err := w.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.Write([]byte("after restart\n"))
+	w.Sync()
+
+	backups, _ := w.Backups()
+	if len(backups) != 2 || backups[0] != backup {
+		t.Fatalf("Expected a rotation after the restart and no unrelated files, got %v", backups)
+	}
+
+	// Without backups the modification time is the start
+	other := filepath.Join(dir, "other.log")
+	os.WriteFile(other, []byte("line\n"), 0644)
+	os.Chtimes(other, old, old)
+	w2 := &logging.RotatingWriter{Path: other, MaxAge: time.Hour}
+	if err := w2.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer w2.Close()
+	w2.Write([]byte("after restart\n"))
+	w2.Sync()
+	if backups, _ := w2.Backups(); len(backups) != 1 {
+		t.Fatalf("Expected a rotation of the old file, got %v", backups)
+	}
+}
+
+func TestRotatingWriterConcurrentWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signals.log")
+	w := &logging.RotatingWriter{Path: path, MaxSize: 256}
+	if err := w.Open(); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				w.Write([]byte("concurrent line\n"))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	// Every line must end up intact in some file
+	files, _ := filepath.Glob(path + "*")
+	var total int
+	for _, f := range files {
+		data, _ := os.ReadFile(f)
+		total += len(data)
+	}
+	if total != 8*50*len("concurrent line\n") {
+		t.Errorf("Expected all bytes written, got %d", total)
+	}
+}
@@ -0,0 +1,101 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gold-analyzer/analysis"
+	"gold-analyzer/metrics"
+	"gold-analyzer/strategy"
+)
+
+func TestMetricsExposition(t *testing.T) {
+	reg := metrics.NewRegistry()
+	price := reg.NewGauge("test_price", "Price.", "symbol")
+	fetches := reg.NewCounter("test_fetches_total", "Fetches.", "symbol")
+	latency := reg.NewHistogram("test_latency_seconds", "Latency.", []float64{0.1, 1}, "symbol")
+
+	price.Set(2500.5, "GC=F")
+	fetches.Inc("GC=F")
+	fetches.Inc("GC=F")
+	latency.Observe(0.05, "GC=F")
+	latency.Observe(0.5, "GC=F")
+
+	rec := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	out := rec.Body.String()
+
+	for _, want := range []string{
+		"# TYPE test_price gauge",
+		`test_price{symbol="GC=F"} 2500.5`,
+		"# TYPE test_fetches_total counter",
+		`test_fetches_total{symbol="GC=F"} 2`,
+		"# TYPE test_latency_seconds histogram",
+		`test_latency_seconds_bucket{symbol="GC=F",le="0.1"} 1`,
+		`test_latency_seconds_bucket{symbol="GC=F",le="1"} 2`,
+		`test_latency_seconds_bucket{symbol="GC=F",le="+Inf"} 2`,
+		`test_latency_seconds_count{symbol="GC=F"} 2`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestMetricsEscaping(t *testing.T) {
+	reg := metrics.NewRegistry()
+	g := reg.NewGauge("test_escape", "Path C:\\logs\nsecond line.", "name")
+	g.Set(1, "طلا \"quoted\"\n\\")
+
+	var b strings.Builder
+	reg.Write(&b)
+	out := b.String()
+
+	for _, want := range []string{
+		`# HELP test_escape Path C:\\logs\nsecond line.`,
+		`test_escape{name="طلا \"quoted\"\n\\"} 1`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("Expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestCounterValueDoesNotCreateSeries(t *testing.T) {
+	reg := metrics.NewRegistry()
+	fetches := reg.NewCounter("test_reads_total", "Reads.", "symbol")
+
+	if v := fetches.Value("SI=F"); v != 0 {
+		t.Errorf("Value of an unseen series = %g, want 0", v)
+	}
+
+	var b strings.Builder
+	reg.Write(&b)
+	if strings.Contains(b.String(), "SI=F") {
+		t.Errorf("Value exported a new series:\n%s", b.String())
+	}
+}
+
+func TestObserveResult(t *testing.T) {
+	metrics.ObserveResult(analysis.Result{
+		Symbol: "TEST",
+		Price:  2500,
+		Signal: strategy.SELL,
+		Indicators: analysis.Indicators{
+			RSI: 70,
+		},
+	})
+
+	var sb strings.Builder
+	metrics.Default.Write(&sb)
+	out := sb.String()
+
+	if !strings.Contains(out, `gold_analyzer_signal{symbol="TEST"} -1`) {
+		t.Errorf("Expected SELL gauge of -1:\n%s", out)
+	}
+	if !strings.Contains(out, `gold_analyzer_rsi{symbol="TEST"} 70`) {
+		t.Errorf("Expected RSI gauge:\n%s", out)
+	}
+}
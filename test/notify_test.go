@@ -0,0 +1,242 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"gold-analyzer/notify"
+)
+
+type fakeNotifier struct {
+	name string
+	err  error
+
+	mu     sync.Mutex
+	events []notify.Event
+}
+
+func (f *fakeNotifier) Name() string { return f.name }
+
+func (f *fakeNotifier) Notify(ctx context.Context, e notify.Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, e)
+	return f.err
+}
+
+func (f *fakeNotifier) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.events)
+}
+
+func TestDispatcherFanOut(t *testing.T) {
+	a := &fakeNotifier{name: "a"}
+	b := &fakeNotifier{name: "b"}
+
+	d := notify.NewDispatcher(nil)
+	d.Register(a, nil)
+	d.Register(b, nil)
+
+	if err := d.Dispatch(context.Background(), notify.NewEvent("signal", "GC=F", "test", 2500)); err != nil {
+		t.Fatalf("Dispatch returned error: %v", err)
+	}
+
+	if a.count() != 1 || b.count() != 1 {
+		t.Errorf("Expected each notifier called once, got a=%d b=%d", a.count(), b.count())
+	}
+	if d.Queue().Len() != 0 {
+		t.Errorf("Expected empty retry queue, got %d", d.Queue().Len())
+	}
+}
+
+func TestDispatcherUnregister(t *testing.T) {
+	a := &fakeNotifier{name: "a"}
+	b := &fakeNotifier{name: "b"}
+
+	d := notify.NewDispatcher(nil)
+	d.Register(a, nil)
+	d.Register(b, nil)
+	d.Unregister("a")
+	d.Unregister("missing")
+
+	if got := d.Channels(); len(got) != 1 || got[0] != "b" {
+		t.Fatalf("Expected channels [b], got %v", got)
+	}
+	if err := d.Dispatch(context.Background(), notify.NewEvent("signal", "GC=F", "test", 2500)); err != nil {
+		t.Fatalf("Dispatch returned error: %v", err)
+	}
+	if a.count() != 0 || b.count() != 1 {
+		t.Errorf("Expected only b notified, got a=%d b=%d", a.count(), b.count())
+	}
+}
+
+func TestDispatcherQueuesFailures(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.json")
+	queue, err := notify.NewQueue(path)
+	if err != nil {
+		t.Fatalf("NewQueue returned error: %v", err)
+	}
+
+	failing := &fakeNotifier{name: "failing", err: errors.New("boom")}
+	ok := &fakeNotifier{name: "ok"}
+
+	d := notify.NewDispatcher(queue)
+	d.Register(failing, nil)
+	d.Register(ok, nil)
+
+	if err := d.Dispatch(context.Background(), notify.NewEvent("signal", "GC=F", "test", 2500)); err == nil {
+		t.Error("Expected error from failing notifier")
+	}
+
+	// The queue must survive a restart
+	restored, err := notify.NewQueue(path)
+	if err != nil {
+		t.Fatalf("Reloading queue returned error: %v", err)
+	}
+	pending := restored.Pending()
+	if len(pending) != 1 {
+		t.Fatalf("Expected 1 pending delivery, got %d", len(pending))
+	}
+	if pending[0].Notifier != "failing" || pending[0].Attempts != 1 {
+		t.Errorf("Unexpected pending delivery: %+v", pending[0])
+	}
+}
+
+func TestDispatcherFlushDeliversPending(t *testing.T) {
+	n := &fakeNotifier{name: "flaky", err: errors.New("down")}
+
+	d := notify.NewDispatcher(nil)
+	d.Register(n, nil)
+	d.Dispatch(context.Background(), notify.NewEvent("signal", "GC=F", "test", 2500))
+
+	// Channel recovered, flush should ignore backoff and deliver
+	n.mu.Lock()
+	n.err = nil
+	n.mu.Unlock()
+
+	if err := d.Flush(context.Background()); err != nil {
+		t.Errorf("Flush returned error: %v", err)
+	}
+	if n.count() != 2 {
+		t.Errorf("Expected 2 delivery attempts, got %d", n.count())
+	}
+	if d.Queue().Len() != 0 {
+		t.Errorf("Expected empty queue after flush, got %d", d.Queue().Len())
+	}
+}
+
+func TestDispatcherFlushReportsDrops(t *testing.T) {
+	d := notify.NewDispatcher(nil)
+	d.MaxAttempts = 2
+	d.Register(&fakeNotifier{name: "down", err: errors.New("down")}, nil)
+	d.Register(&fakeNotifier{name: "removed", err: errors.New("down")}, nil)
+	d.Dispatch(context.Background(), notify.NewEvent("signal", "GC=F", "test", 2500))
+	d.Unregister("removed")
+
+	// "down" runs out of attempts, "removed" is no longer registered
+	err := d.Flush(context.Background())
+	if !errors.Is(err, notify.ErrDropped) {
+		t.Fatalf("Flush error = %v, want %v", err, notify.ErrDropped)
+	}
+	for _, name := range []string{"down", "removed"} {
+		if !strings.Contains(err.Error(), name+": "+notify.ErrDropped.Error()) {
+			t.Errorf("Flush error does not report the %s drop: %v", name, err)
+		}
+	}
+	if d.Queue().Len() != 0 {
+		t.Errorf("Expected empty queue after the drops, got %d", d.Queue().Len())
+	}
+}
+
+// cancellingNotifier fails like a delivery cut off by shutdown
+type cancellingNotifier struct{ cancel context.CancelFunc }
+
+func (c *cancellingNotifier) Name() string { return "slow" }
+
+func (c *cancellingNotifier) Notify(ctx context.Context, e notify.Event) error {
+	c.cancel()
+	return ctx.Err()
+}
+
+func TestDispatcherFlushKeepsAttemptsOnShutdown(t *testing.T) {
+	d := notify.NewDispatcher(nil)
+	d.MaxAttempts = 2
+	d.Register(&fakeNotifier{name: "slow", err: errors.New("down")}, nil)
+	d.Dispatch(context.Background(), notify.NewEvent("signal", "GC=F", "test", 2500))
+
+	// The last attempt is cancelled by shutdown rather than failing
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d.Register(&cancellingNotifier{cancel: cancel}, nil)
+
+	err := d.Flush(ctx)
+	if errors.Is(err, notify.ErrDropped) {
+		t.Fatalf("Flush dropped a cancelled delivery: %v", err)
+	}
+	pending := d.Queue().Pending()
+	if len(pending) != 1 || pending[0].Attempts != 1 {
+		t.Errorf("pending = %+v, want one delivery after 1 attempt", pending)
+	}
+}
+
+func TestQueueMovesCorruptFileAside(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	queue, err := notify.NewQueue(path)
+	if err == nil {
+		t.Fatal("NewQueue accepted a corrupt file")
+	}
+	if err := queue.Push(notify.Delivery{Notifier: "webhook"}); err != nil {
+		t.Fatalf("Push returned error: %v", err)
+	}
+	data, err := os.ReadFile(path + ".corrupt")
+	if err != nil || string(data) != "{not json" {
+		t.Errorf("corrupt file = %q, %v; want it kept aside", data, err)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := notify.NewRateLimiter(1, 2)
+
+	if !limiter.Allow() || !limiter.Allow() {
+		t.Error("Expected burst of 2 to be allowed")
+	}
+	if limiter.Allow() {
+		t.Error("Expected third call to be rate limited")
+	}
+
+	// nil limiter means unlimited
+	var unlimited *notify.RateLimiter
+	if !unlimited.Allow() {
+		t.Error("Expected nil limiter to allow")
+	}
+}
+
+func TestDispatcherRateLimitQueues(t *testing.T) {
+	n := &fakeNotifier{name: "limited"}
+
+	d := notify.NewDispatcher(nil)
+	d.Register(n, notify.NewRateLimiter(1, 1))
+
+	d.Dispatch(context.Background(), notify.NewEvent("signal", "GC=F", "first", 2500))
+	err := d.Dispatch(context.Background(), notify.NewEvent("signal", "GC=F", "second", 2500))
+
+	if !errors.Is(err, notify.ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+	if n.count() != 1 {
+		t.Errorf("Expected 1 delivery, got %d", n.count())
+	}
+	if d.Queue().Len() != 1 {
+		t.Errorf("Expected rate limited event queued, got %d", d.Queue().Len())
+	}
+}
@@ -0,0 +1,109 @@
+package test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"gold-analyzer/analysis"
+	"gold-analyzer/output"
+	"gold-analyzer/strategy"
+)
+
+func sampleResult(price float64, sig strategy.Signal) analysis.Result {
+	ts := time.Date(2025, time.March, 21, 12, 0, 0, 0, time.UTC)
+	return analysis.Result{
+		Symbol:        "GC=F",
+		Interval:      "5m",
+		Time:          ts,
+		CandleTime:    ts.Add(-5 * time.Minute),
+		Price:         price,
+		Change:        1.5,
+		ChangePercent: 0.06,
+		Indicators: analysis.Indicators{
+			RSIPeriod: 14, RSI: 48.2,
+			MACD: 0.5, MACDSignal: 0.25, MACDHist: 0.25,
+			ATRPeriod: 14, ATR: 3.1,
+		},
+		Signal: sig,
+	}
+}
+
+func TestNDJSONOutput(t *testing.T) {
+	var buf bytes.Buffer
+	r, err := output.New("ndjson", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Render(sampleResult(2500, strategy.BUY))
+	r.Render(sampleResult(2501, strategy.HOLD))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d: %q", len(lines), buf.String())
+	}
+
+	var got analysis.Result
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Symbol != "GC=F" || got.Price != 2500 || got.Signal != strategy.BUY || got.Indicators.RSI != 48.2 {
+		t.Errorf("Unexpected record: %+v", got)
+	}
+}
+
+func TestJSONOutputIsIndented(t *testing.T) {
+	var buf bytes.Buffer
+	r, err := output.New("JSON", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Render(sampleResult(2500, strategy.SELL))
+
+	if !strings.Contains(buf.String(), "\n  \"symbol\": \"GC=F\"") {
+		t.Errorf("Expected indented JSON object, got %q", buf.String())
+	}
+	var got analysis.Result
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+}
+
+func TestCSVOutputWritesHeaderOnce(t *testing.T) {
+	var buf bytes.Buffer
+	r, err := output.New("csv", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Render(sampleResult(2500, strategy.BUY))
+	r.Render(sampleResult(2499.75, strategy.SELL))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %d", len(rows))
+	}
+	if strings.Join(rows[0], ",") != strings.Join(output.Header, ",") {
+		t.Errorf("Unexpected header: %v", rows[0])
+	}
+	if rows[2][0] != "2025-03-21T12:00:00Z" || rows[2][4] != "2499.75" || rows[2][12] != "SELL" {
+		t.Errorf("Unexpected row: %v", rows[2])
+	}
+}
+
+func TestOutputRejectsUnknownFormat(t *testing.T) {
+	if _, err := output.ParseFormat("xml"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+	if f, err := output.ParseFormat(""); err != nil || f != output.Console {
+		t.Errorf("Expected console by default, got %q, %v", f, err)
+	}
+	if _, err := output.New("console", &bytes.Buffer{}); err == nil {
+		t.Error("Expected console to have no machine renderer")
+	}
+}
@@ -0,0 +1,97 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"gold-analyzer/analysis"
+	"gold-analyzer/config"
+	"gold-analyzer/i18n"
+	"gold-analyzer/model"
+	"gold-analyzer/report"
+	"gold-analyzer/strategy"
+)
+
+// waveCandles returns hourly candles oscillating around 2500
+func waveCandles(n int) []model.Candle {
+	start := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC).Unix()
+	candles := make([]model.Candle, n)
+	for i := range candles {
+		p := 2500 + 30*math.Sin(float64(i)/8) + float64(i%3)
+		candles[i] = model.Candle{
+			Time:  start + int64(i)*3600,
+			Open:  p - 1,
+			High:  p + 2 + float64(i%5),
+			Low:   p - 2,
+			Close: p,
+		}
+	}
+	return candles
+}
+
+func TestBuildSeriesMatchesLiveAnalysis(t *testing.T) {
+	cfg := config.DefaultConfig()
+	candles := waveCandles(200)
+
+	s, err := analysis.BuildSeries(context.Background(), cfg, candles)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Replaying bar i must give the signal a live run would have produced then
+	for _, i := range []int{s.WarmUp, 80, 150, 199} {
+		res, err := analysis.Analyze(context.Background(), cfg, candles[:i+1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Signals[i] != res.Signal {
+			t.Errorf("Bar %d: series signal %s, live signal %s", i, s.Signals[i], res.Signal)
+		}
+	}
+
+	for _, m := range s.Markers() {
+		if m.Signal == strategy.HOLD || s.Signals[m.Index] != m.Signal {
+			t.Errorf("Unexpected marker %+v", m)
+		}
+	}
+
+	if _, err := analysis.BuildSeries(context.Background(), cfg, candles[:10]); err == nil {
+		t.Error("Expected error for too few candles")
+	}
+}
+
+func TestReportIsSelfContainedHTML(t *testing.T) {
+	i18n.SetDefault(i18n.New(i18n.EN))
+	defer i18n.SetDefault(i18n.New(i18n.DefaultLang))
+
+	cfg := config.DefaultConfig()
+	s, err := analysis.BuildSeries(context.Background(), cfg, waveCandles(200))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := report.Write(&buf, cfg, s, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	if n := strings.Count(out, "<svg"); n != 4 {
+		t.Errorf("Expected 4 charts, got %d", n)
+	}
+	for _, want := range []string{"<!DOCTYPE html>", `dir="ltr"`, "Gold Analyzer report — GC=F", "MACD histogram"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Report missing %q", want)
+		}
+	}
+	if strings.Contains(out, "<script") || strings.Count(out, "http") != strings.Count(out, "http://www.w3.org/2000/svg") {
+		t.Error("Expected no scripts or external resources")
+	}
+	if len(s.Markers()) > 0 && !strings.Contains(out, "<polygon") {
+		t.Error("Expected BUY/SELL markers on the price chart")
+	}
+}
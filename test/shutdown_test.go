@@ -1,12 +1,19 @@
 package test
 
 import (
+	"context"
+	"errors"
+	"log/slog"
 	"os"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"gold-analyzer/analysis"
+	"gold-analyzer/config"
 	"gold-analyzer/shutdown"
+	"gold-analyzer/yahoo"
 )
 
 func TestShutdownManagerCreation(t *testing.T) {
@@ -186,6 +193,290 @@ func TestShutdownManagerDoubleShutdown(t *testing.T) {
 	}
 }
 
+func TestShutdownManagerHookOrder(t *testing.T) {
+	mgr := shutdown.NewManager()
+
+	var order []string
+	add := func(name string, phase shutdown.Phase, priority int) {
+		mgr.Register(shutdown.Hook{
+			Name:     name,
+			Phase:    phase,
+			Priority: priority,
+			Fn: func(context.Context) error {
+				order = append(order, name)
+				return nil
+			},
+		})
+	}
+	add("logs", shutdown.PhaseFinal, 1)
+	add("stats", shutdown.PhaseFinal, 0)
+	add("storage", shutdown.PhaseClose, 0)
+	add("notifier", shutdown.PhaseFlush, 0)
+	add("api", shutdown.PhaseStopIntake, 0)
+
+	if err := mgr.Shutdown(time.Second); err != nil {
+		t.Fatalf("Shutdown returned error: %v", err)
+	}
+	want := []string{"api", "notifier", "storage", "stats", "logs"}
+	if strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Errorf("hooks ran in order %v, want %v", order, want)
+	}
+}
+
+func TestShutdownManagerHookErrors(t *testing.T) {
+	mgr := shutdown.NewManager()
+
+	errFlush := errors.New("queue file is read-only")
+	mgr.Register(shutdown.Hook{
+		Name:    "slow",
+		Phase:   shutdown.PhaseStopIntake,
+		Timeout: 50 * time.Millisecond,
+		Fn: func(ctx context.Context) error {
+			time.Sleep(2 * time.Second)
+			return nil
+		},
+	})
+	mgr.Register(shutdown.Hook{
+		Name:  "notifier",
+		Phase: shutdown.PhaseFlush,
+		Fn:    func(context.Context) error { return errFlush },
+	})
+	ran := false
+	mgr.Register(shutdown.Hook{
+		Name:  "stats",
+		Phase: shutdown.PhaseFinal,
+		Fn: func(context.Context) error {
+			ran = true
+			return nil
+		},
+	})
+
+	start := time.Now()
+	err := mgr.Shutdown(time.Second)
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("Shutdown took %v despite the hook timeout", d)
+	}
+
+	// A timed out or failing hook does not stop the later ones
+	if !ran {
+		t.Error("hook after the failures did not run")
+	}
+	if !errors.Is(err, shutdown.ErrTimeout) || !errors.Is(err, errFlush) {
+		t.Fatalf("Shutdown error = %v, want the timeout and the flush error", err)
+	}
+
+	var hookErr *shutdown.HookError
+	if !errors.As(err, &hookErr) || hookErr.Hook != "slow" {
+		t.Errorf("first hook error = %v, want the slow hook", hookErr)
+	}
+	if !strings.Contains(err.Error(), "notifier: queue file is read-only") {
+		t.Errorf("error does not name the failing hook: %v", err)
+	}
+	if mgr.GetLastError() == nil {
+		t.Error("GetLastError is nil after failed hooks")
+	}
+}
+
+func TestShutdownManagerForcedExit(t *testing.T) {
+	mgr := shutdown.NewManager()
+	exited := make(chan int, 1)
+	mgr.Exit = func(code int) { exited <- code }
+	mgr.Start()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	mgr.Register(shutdown.Hook{
+		Name: "stuck",
+		Fn: func(context.Context) error {
+			close(started)
+			<-release
+			return nil
+		},
+	})
+	mgr.Register(shutdown.Hook{Name: "stats", Phase: shutdown.PhaseFinal, Fn: func(context.Context) error { return nil }})
+
+	mgr.Notify(os.Interrupt)
+	if sig := mgr.WaitForShutdown(); sig != os.Interrupt {
+		t.Fatalf("WaitForShutdown = %v", sig)
+	}
+	go mgr.Shutdown(time.Minute)
+
+	<-started
+	if p := mgr.Pending(); strings.Join(p, ",") != "stuck,stats" {
+		t.Errorf("Pending = %v, want [stuck stats]", p)
+	}
+	select {
+	case code := <-exited:
+		t.Fatalf("exit forced with code %d before a second signal", code)
+	default:
+	}
+
+	// A second signal counts however soon it follows the first
+	mgr.Notify(os.Interrupt)
+	select {
+	case code := <-exited:
+		if code != shutdown.ExitForced {
+			t.Errorf("exit code = %d, want %d", code, shutdown.ExitForced)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("second signal did not force the exit")
+	}
+}
+
+func TestShutdownManagerTimedOutHookStaysPending(t *testing.T) {
+	mgr := shutdown.NewManager()
+	mgr.Observer = nil
+
+	release, returned := make(chan struct{}), make(chan struct{})
+	mgr.Register(shutdown.Hook{
+		Name:    "stuck",
+		Timeout: 20 * time.Millisecond,
+		Fn: func(context.Context) error {
+			defer close(returned)
+			<-release
+			return nil
+		},
+	})
+	var during []string
+	mgr.Register(shutdown.Hook{
+		Name:  "stats",
+		Phase: shutdown.PhaseFinal,
+		Fn: func(context.Context) error {
+			during = mgr.Pending()
+			return nil
+		},
+	})
+
+	mgr.Shutdown(time.Minute)
+
+	// The abandoned hook overlaps the later phase and is still reported
+	if strings.Join(during, ",") != "stuck,stats" {
+		t.Errorf("Pending during stats = %v, want [stuck stats]", during)
+	}
+	if p := mgr.Pending(); strings.Join(p, ",") != "stuck" {
+		t.Errorf("Pending after Shutdown = %v, want [stuck]", p)
+	}
+
+	close(release)
+	<-returned
+	deadline := time.Now().Add(time.Second)
+	for len(mgr.Pending()) > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if p := mgr.Pending(); len(p) != 0 {
+		t.Errorf("Pending after the hook returned = %v, want none", p)
+	}
+}
+
+// recorder collects shutdown events
+type recorder struct {
+	mu     sync.Mutex
+	events []shutdown.Event
+}
+
+func (r *recorder) ShutdownEvent(e shutdown.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+func TestShutdownManagerObserver(t *testing.T) {
+	mgr := shutdown.NewManager()
+	rec := &recorder{}
+	var console strings.Builder
+	mgr.Observer = shutdown.Observers{rec, &shutdown.ConsoleObserver{Out: &console}}
+
+	errClose := errors.New("disk full")
+	mgr.Register(shutdown.Hook{Name: "api", Fn: func(context.Context) error { return nil }})
+	mgr.Register(shutdown.Hook{Name: "storage", Phase: shutdown.PhaseClose, Fn: func(context.Context) error { return errClose }})
+	mgr.Register(shutdown.Hook{
+		Name:    "stats",
+		Phase:   shutdown.PhaseFinal,
+		Timeout: 20 * time.Millisecond,
+		Fn: func(ctx context.Context) error {
+			<-ctx.Done()
+			time.Sleep(time.Second)
+			return nil
+		},
+	})
+
+	if err := mgr.Shutdown(time.Second); err == nil {
+		t.Fatal("Shutdown returned nil despite failing hooks")
+	}
+
+	var got []string
+	for _, e := range rec.events {
+		got = append(got, string(e.Type)+" "+e.Hook)
+	}
+	want := []string{"started ", "hook_done api", "hook_failed storage", "hook_timeout stats", "completed "}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("events = %q, want %q", got, want)
+	}
+
+	done := rec.events[len(rec.events)-1]
+	if done.Hooks != 3 || done.Failed != 2 || !errors.Is(done.Err, errClose) || done.Duration <= 0 {
+		t.Errorf("completed event = %+v", done)
+	}
+	if !strings.Contains(console.String(), "storage") || !strings.Contains(console.String(), "stats") {
+		t.Errorf("console output does not name the failed hooks:\n%s", console.String())
+	}
+}
+
+func TestLogObserverForcedWithoutSignal(t *testing.T) {
+	var out strings.Builder
+	obs := &shutdown.LogObserver{Logger: slog.New(slog.NewTextHandler(&out, nil))}
+
+	obs.ShutdownEvent(shutdown.Event{Type: shutdown.EventForced, Pending: []string{"state"}})
+	if !strings.Contains(out.String(), "shutdown forced") || !strings.Contains(out.String(), "pending=[state]") {
+		t.Errorf("log = %q", out.String())
+	}
+}
+
+func TestShutdownManagerContext(t *testing.T) {
+	mgr := shutdown.NewManager()
+	ctx := mgr.Context()
+
+	if ctx.Err() != nil {
+		t.Fatal("Context should not be done before Stop()")
+	}
+
+	mgr.Stop()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Context was not cancelled by Stop()")
+	}
+
+	// Stop no longer skips the hooks
+	called := false
+	mgr.RegisterHook(func() error {
+		called = true
+		return nil
+	})
+	if err := mgr.Shutdown(time.Second); err != nil {
+		t.Errorf("Shutdown returned error: %v", err)
+	}
+	if !called {
+		t.Error("Hook was not called after Stop()")
+	}
+}
+
+func TestShutdownCancelsInFlightWork(t *testing.T) {
+	mgr := shutdown.NewManager()
+	mgr.Stop()
+
+	start := time.Now()
+	if _, err := yahoo.FetchCandles(mgr.Context(), "GC=F", "1h", "7d"); !errors.Is(err, context.Canceled) {
+		t.Errorf("FetchCandles error = %v, want context.Canceled", err)
+	}
+	if _, err := analysis.Analyze(mgr.Context(), config.DefaultConfig(), nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Analyze error = %v, want context.Canceled", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("cancelled work took %v", d)
+	}
+}
+
 func TestShutdownManagerSignalHandling(t *testing.T) {
 	// This test would require signal mocking
 	// For now, we just verify the manager can be created and used
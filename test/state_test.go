@@ -0,0 +1,137 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gold-analyzer/alerts"
+	"gold-analyzer/backtest"
+	"gold-analyzer/notify"
+	"gold-analyzer/state"
+	"gold-analyzer/strategy"
+)
+
+func TestStateSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	// No file yet: an empty snapshot
+	snap, err := state.Load(path)
+	if err != nil || len(snap.Symbols) != 0 || !snap.SavedAt.IsZero() {
+		t.Fatalf("Load of a missing file = %+v, %v", snap, err)
+	}
+
+	candle := time.Date(2025, 12, 14, 10, 0, 0, 0, time.UTC)
+	snap.Symbols["GC=F"] = state.Symbol{Signal: strategy.BUY, CandleTime: candle, Price: 2650.5,
+		Position: &backtest.Trade{EntryTime: candle, EntryPrice: 2650.5, Open: true}}
+	snap.Alerts = []alerts.RuleState{{Rule: "r", Active: true, Last: 2510, Fired: candle, FiredCandle: candle}}
+	snap.Pending = []notify.Delivery{{Notifier: "webhook", Event: notify.NewEvent("signal", "GC=F", "m", 2650.5), Attempts: 1}}
+	if err := state.Save(path, snap); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, err := state.Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if s := got.Symbols["GC=F"]; s.Signal != strategy.BUY || !s.CandleTime.Equal(candle) || s.Price != 2650.5 {
+		t.Errorf("symbol state = %+v", s)
+	}
+	if p := got.Symbols["GC=F"].Position; p == nil || p.EntryPrice != 2650.5 || !p.EntryTime.Equal(candle) {
+		t.Errorf("position = %+v", p)
+	}
+	if len(got.Alerts) != 1 || !got.Alerts[0].Active || !got.Alerts[0].Fired.Equal(candle) || got.SavedAt.IsZero() {
+		t.Errorf("snapshot = %+v", got)
+	}
+	if len(got.Pending) != 1 || got.Pending[0].Notifier != "webhook" || got.Pending[0].Attempts != 1 {
+		t.Errorf("pending = %+v", got.Pending)
+	}
+
+	// No temp files are left behind
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("directory has %d entries, want only the state file", len(entries))
+	}
+}
+
+func TestStateLoadRejectsOtherVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte(`{"version": 99, "symbols": {}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := state.Load(path); err == nil || !strings.Contains(err.Error(), "version 99") {
+		t.Errorf("Load error = %v, want unsupported version", err)
+	}
+}
+
+func TestAlertStateRestoreDoesNotRefire(t *testing.T) {
+	rules := []alerts.Rule{{Name: "below 2000", Type: alerts.TypePrice, Condition: alerts.CondBelow, Value: 2000}}
+	feed := alerts.Feed{Symbol: "GC=F", Interval: "1h"}
+
+	before := alerts.NewEngine(rules, "GC=F", "1h")
+	if fired, _ := before.Evaluate(feed, candlesFromCloses(1990, 1980)); len(fired) != 1 {
+		t.Fatalf("Expected alert before the restart, got %d", len(fired))
+	}
+
+	// After a restart the restored engine remembers the alert is active
+	after := alerts.NewEngine(rules, "GC=F", "1h")
+	if n := after.Restore(before.State()); n != 1 {
+		t.Errorf("Restore = %d, want 1", n)
+	}
+	if fired, _ := after.Evaluate(feed, candlesFromCloses(1980, 1975)); len(fired) != 0 {
+		t.Errorf("Expected no repeated alert after restore, got %d", len(fired))
+	}
+
+	// A changed rule starts fresh
+	changed := []alerts.Rule{{Name: "below 2000", Type: alerts.TypePrice, Condition: alerts.CondBelow, Value: 1990}}
+	if n := alerts.NewEngine(changed, "GC=F", "1h").Restore(before.State()); n != 0 {
+		t.Errorf("Restore of a changed rule = %d, want 0", n)
+	}
+}
+
+func TestAlertStateRestoreKeepsCooldown(t *testing.T) {
+	rules := []alerts.Rule{{Name: "cross 2000", Type: alerts.TypePrice, Condition: alerts.CondCrossAbove, Value: 2000, Cooldown: "1h"}}
+	feed := alerts.Feed{Symbol: "GC=F", Interval: "1h"}
+
+	before := alerts.NewEngine(rules, "GC=F", "1h")
+	if fired, _ := before.Evaluate(feed, candlesFromCloses(1990, 2010)); len(fired) != 1 {
+		t.Fatalf("Expected alert before the restart, got %d", len(fired))
+	}
+	if fired, _ := before.Evaluate(feed, candlesFromCloses(2010, 1990, 1980)); len(fired) != 0 {
+		t.Fatalf("Expected no alert below the value, got %d", len(fired))
+	}
+
+	// A new crossing within the cooldown stays quiet after a restart
+	after := alerts.NewEngine(rules, "GC=F", "1h")
+	after.Restore(before.State())
+	if fired, _ := after.Evaluate(feed, candlesFromCloses(2010, 1990, 1980, 2020)); len(fired) != 0 {
+		t.Errorf("Expected no alert within the cooldown, got %d", len(fired))
+	}
+}
+
+func TestAlertStateRestoreSkipsFiredCandle(t *testing.T) {
+	rules := []alerts.Rule{{Name: "cross 2000", Type: alerts.TypePrice, Condition: alerts.CondCrossAbove, Value: 2000}}
+	feed := alerts.Feed{Symbol: "GC=F", Interval: "1h"}
+
+	before := alerts.NewEngine(rules, "GC=F", "1h")
+	if fired, _ := before.Evaluate(feed, candlesFromCloses(1990, 2010)); len(fired) != 1 {
+		t.Fatalf("Expected alert before the restart, got %d", len(fired))
+	}
+
+	// The live candle dipped and crossed again, but the alert already
+	// fired on it before the restart
+	states := before.State()
+	states[0].Last = 1995
+	after := alerts.NewEngine(rules, "GC=F", "1h")
+	after.Restore(states)
+	if fired, _ := after.Evaluate(feed, candlesFromCloses(1990, 2015)); len(fired) != 0 {
+		t.Errorf("Expected no repeated alert on the same candle, got %d", len(fired))
+	}
+
+	// A crossing on the next candle fires again
+	after.Evaluate(feed, candlesFromCloses(1990, 1985))
+	if fired, _ := after.Evaluate(feed, candlesFromCloses(1990, 1985, 2020)); len(fired) != 1 {
+		t.Errorf("Expected an alert on a new candle, got %d", len(fired))
+	}
+}
@@ -0,0 +1,58 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"gold-analyzer/analysis"
+	"gold-analyzer/config"
+	"gold-analyzer/strategy"
+)
+
+func TestStrategyMinBars(t *testing.T) {
+	// MACD 8/21/5 needs the most history: its histogram starts at index 24
+	if got := strategy.MinBars(14, 8, 21, 5, 14); got != 25 {
+		t.Errorf("MinBars = %d, want 25", got)
+	}
+	if got := strategy.MinBars(14, 12, 26, 9, 14); got != 34 {
+		t.Errorf("MinBars(12/26/9) = %d, want 34", got)
+	}
+	// A long ATR needs its previous bar warmed up too
+	if got := strategy.MinBars(14, 8, 21, 5, 30); got != 32 {
+		t.Errorf("MinBars(ATR 30) = %d, want 32", got)
+	}
+}
+
+func TestAnalyzeRefusesShortHistory(t *testing.T) {
+	cfg := config.DefaultConfig()
+
+	_, err := analysis.Analyze(context.Background(), cfg, waveCandles(20))
+	if !errors.Is(err, strategy.ErrInsufficientHistory) || !strings.Contains(err.Error(), "need 25 bars, got 20") {
+		t.Fatalf("Analyze error = %v, want need 25 bars, got 20", err)
+	}
+
+	res, err := analysis.Analyze(context.Background(), cfg, waveCandles(25))
+	if err != nil {
+		t.Fatalf("Analyze with enough bars: %v", err)
+	}
+	if res.Signal == "" {
+		t.Error("no signal with enough bars")
+	}
+}
+
+func TestEvaluateRefusesWarmUpValues(t *testing.T) {
+	nan := []float64{math.NaN(), math.NaN()}
+
+	sig, err := strategy.Evaluate(strategy.DefaultParams, []float64{50, 50}, nan, []float64{1, 2}, 100)
+	if sig != strategy.HOLD || !errors.Is(err, strategy.ErrInsufficientHistory) {
+		t.Errorf("Evaluate = %s, %v; want HOLD and insufficient history", sig, err)
+	}
+
+	// RSI 0 during warm-up used to look extremely oversold
+	if sig := strategy.GoldStrategy(nan, []float64{1, 1}, []float64{1, 2}, 100); sig != strategy.HOLD {
+		t.Errorf("GoldStrategy on warm-up values = %s, want HOLD", sig)
+	}
+}
@@ -0,0 +1,132 @@
+package test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"gold-analyzer/analysis"
+	"gold-analyzer/config"
+	"gold-analyzer/health"
+	"gold-analyzer/i18n"
+	"gold-analyzer/model"
+	"gold-analyzer/strategy"
+	"gold-analyzer/tui"
+)
+
+func TestSparklineScalesToRange(t *testing.T) {
+	got := tui.Sparkline([]float64{1, 2, 3, 4, 5, 6, 7, 8, 9}, 9)
+	if got != "▁▁▂▃▄▅▆▇█" {
+		t.Errorf("Unexpected sparkline: %q", got)
+	}
+
+	// Only the last width values are drawn
+	if n := utf8.RuneCountInString(tui.Sparkline(make([]float64, 50), 20)); n != 20 {
+		t.Errorf("Expected 20 columns, got %d", n)
+	}
+}
+
+func TestChartRows(t *testing.T) {
+	rows := tui.Chart([]float64{0, 50, 100}, 10, 2, 0, 100)
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(rows))
+	}
+	if rows[0] != "  █" || rows[1] != "▁██" {
+		t.Errorf("Unexpected chart: %q", rows)
+	}
+}
+
+func TestBarsSplitBySign(t *testing.T) {
+	rows := tui.Bars([]float64{2, -2, 0}, 10, 2)
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(rows))
+	}
+	if rows[0] != "█  " || rows[1] != " █ " {
+		t.Errorf("Unexpected bars: %q", rows)
+	}
+}
+
+func TestDashboardFrame(t *testing.T) {
+	i18n.SetDefault(i18n.New(i18n.EN))
+	defer i18n.SetDefault(i18n.New(i18n.DefaultLang))
+
+	cfg := config.DefaultConfig()
+	candles := make([]model.Candle, 60)
+	for i := range candles {
+		p := 2500 + float64(i%7)
+		candles[i] = model.Candle{Open: p, High: p + 2, Low: p - 2, Close: p}
+	}
+
+	now := time.Date(2025, time.March, 21, 12, 0, 0, 0, time.UTC)
+	d := tui.New(cfg)
+	d.Color = false
+	d.Width = 60
+
+	frame := d.Frame(tui.State{
+		Result: analysis.Result{
+			Time:       now,
+			Price:      2506,
+			Signal:     strategy.BUY,
+			Indicators: analysis.Indicators{RSIPeriod: 14, RSI: 50, ATRPeriod: 14, ATR: 4},
+		},
+		Candles: candles,
+		Reasons: []string{"   Reasons for BUY:"},
+		History: []analysis.SignalChange{{Time: now, Signal: strategy.BUY, Price: 2506}},
+		Fetch:   health.Status{LastSuccess: now},
+	}, now)
+
+	for _, want := range []string{"Price: 2506.00", "RSI (14): 50.00", "Signal: BUY", "Reasons for BUY:", "→ BUY", "Last fetch:"} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("Frame missing %q:\n%s", want, frame)
+		}
+	}
+	if strings.Contains(frame, "\x1b[") {
+		t.Error("Expected no escape sequences with colors disabled")
+	}
+}
+
+func TestDashboardDrawsTargetConfig(t *testing.T) {
+	i18n.SetDefault(i18n.New(i18n.EN))
+	defer i18n.SetDefault(i18n.New(i18n.DefaultLang))
+
+	d := tui.New(config.DefaultConfig())
+	d.Color = false
+	target := config.DefaultConfig()
+	target.Symbol, target.Interval = "SI=F", "15m"
+	target.RSIBuyLower, target.RSISellThreshold = 25, 80
+	d.SetConfig(target)
+
+	now := time.Date(2025, time.March, 21, 12, 0, 0, 0, time.UTC)
+	candles := make([]model.Candle, 60)
+	for i := range candles {
+		p := 30 + float64(i%5)
+		candles[i] = model.Candle{Open: p, High: p + 1, Low: p - 1, Close: p}
+	}
+	frame := d.Frame(tui.State{
+		Result:  analysis.Result{Time: now, Price: 32, Indicators: analysis.Indicators{RSIPeriod: 14, RSI: 20}},
+		Candles: candles,
+	}, now)
+
+	for _, want := range []string{"SI=F", "15m", "100 · 80", "0 · 25"} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("Frame missing %q of the target config:\n%s", want, frame)
+		}
+	}
+}
+
+func TestDashboardRestoresScreen(t *testing.T) {
+	var buf bytes.Buffer
+	d := tui.New(config.DefaultConfig())
+	d.Out = &buf
+
+	d.Draw(tui.State{})
+	d.Close()
+	d.Draw(tui.State{})
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "\x1b[?1049h") || !strings.HasSuffix(out, "\x1b[?1049l") {
+		t.Errorf("Expected alternate screen to be entered and left, got %q", out)
+	}
+}
@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"math"
+	"strings"
+)
+
+// blocks are the eighth-height bar characters, from empty to full
+var blocks = []rune(" ▁▂▃▄▅▆▇█")
+
+// tail returns the last n values
+func tail(values []float64, n int) []float64 {
+	if n > 0 && len(values) > n {
+		return values[len(values)-n:]
+	}
+	return values
+}
+
+// bounds returns the smallest and largest value
+func bounds(values []float64) (lo, hi float64) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	return lo, hi
+}
+
+// Sparkline renders the last width values as a single line of bars
+func Sparkline(values []float64, width int) string {
+	rows := Chart(values, width, 1, 0, 0)
+	if len(rows) == 0 {
+		return ""
+	}
+	return rows[0]
+}
+
+// Chart renders the last width values as an area chart of height rows,
+// top row first. When lo equals hi the range is taken from the values.
+func Chart(values []float64, width, height int, lo, hi float64) []string {
+	values = tail(values, width)
+	if len(values) == 0 || height <= 0 {
+		return nil
+	}
+	if lo == hi {
+		lo, hi = bounds(values)
+	}
+
+	span := hi - lo
+	levels := make([]int, len(values))
+	for i, v := range values {
+		frac := 0.5
+		if span > 0 {
+			frac = (v - lo) / span
+		}
+		frac = math.Max(0, math.Min(1, frac))
+		// At least one eighth so the lowest value stays visible
+		levels[i] = max(1, int(math.Round(frac*float64(height*8))))
+	}
+
+	rows := make([]string, height)
+	for r := 0; r < height; r++ {
+		base := (height - 1 - r) * 8
+		var b strings.Builder
+		for _, l := range levels {
+			b.WriteRune(blocks[min(8, max(0, l-base))])
+		}
+		rows[r] = b.String()
+	}
+	return rows
+}
+
+// Bars renders signed values as bars around a zero line: positive values
+// grow up from the middle and negative values grow down. It returns height
+// rows rounded up to even; the upper half holds only positive bars.
+func Bars(values []float64, width, height int) []string {
+	values = tail(values, width)
+	if len(values) == 0 || height <= 0 {
+		return nil
+	}
+	half := (height + 1) / 2
+
+	var peak float64
+	for _, v := range values {
+		peak = math.Max(peak, math.Abs(v))
+	}
+
+	signs := make([]int, len(values))
+	levels := make([]int, len(values))
+	for i, v := range values {
+		if peak > 0 {
+			levels[i] = int(math.Round(math.Abs(v) / peak * float64(half*8)))
+		}
+		switch {
+		case v > 0:
+			signs[i] = 1
+		case v < 0:
+			signs[i] = -1
+		}
+	}
+
+	rows := make([]string, 0, half*2)
+	for r := 0; r < half; r++ {
+		base := (half - 1 - r) * 8
+		var b strings.Builder
+		for i, l := range levels {
+			if signs[i] > 0 {
+				b.WriteRune(blocks[min(8, max(0, l-base))])
+			} else {
+				b.WriteRune(' ')
+			}
+		}
+		rows = append(rows, b.String())
+	}
+	for r := 0; r < half; r++ {
+		var b strings.Builder
+		for i, l := range levels {
+			// Downward bars are drawn in whole cells
+			if signs[i] < 0 && (l+7)/8 > r {
+				b.WriteRune('█')
+			} else {
+				b.WriteRune(' ')
+			}
+		}
+		rows = append(rows, b.String())
+	}
+	return rows
+}
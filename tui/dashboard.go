@@ -0,0 +1,290 @@
+// Package tui draws a full-screen terminal dashboard that is redrawn on
+// every analysis tick instead of scrolling console output.
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gold-analyzer/analysis"
+	"gold-analyzer/config"
+	"gold-analyzer/health"
+	"gold-analyzer/i18n"
+	"gold-analyzer/indicators"
+	"gold-analyzer/model"
+	"gold-analyzer/strategy"
+)
+
+// ANSI escape sequences
+const (
+	altScreen  = "\x1b[?1049h"
+	mainScreen = "\x1b[?1049l"
+	hideCursor = "\x1b[?25l"
+	showCursor = "\x1b[?25h"
+	clearHome  = "\x1b[H\x1b[2J"
+
+	bold   = "\x1b[1m"
+	dim    = "\x1b[2m"
+	red    = "\x1b[31m"
+	green  = "\x1b[32m"
+	yellow = "\x1b[33m"
+	cyan   = "\x1b[36m"
+	reset  = "\x1b[0m"
+)
+
+// Chart sizes in rows
+const (
+	priceRows   = 8
+	rsiRows     = 4
+	macdRows    = 4
+	historySize = 5
+	// labelWidth is reserved right of the charts for axis labels
+	labelWidth = 12
+)
+
+// State is everything shown on one frame
+type State struct {
+	// Result of the last successful analysis (zero before the first one)
+	Result analysis.Result
+	// Candles the result was computed from
+	Candles []model.Candle
+	// Reasons explaining the signal, one per line
+	Reasons []string
+	// History of signal transitions, oldest first
+	History []analysis.SignalChange
+	// Fetch outcome of the last attempt
+	Fetch health.Status
+}
+
+// Dashboard renders State frames to a terminal
+type Dashboard struct {
+	// Out is the terminal (stdout by default)
+	Out io.Writer
+	// Width in columns (COLUMNS or 80 by default)
+	Width int
+	// Color enables ANSI colors (disabled by NO_COLOR)
+	Color bool
+
+	cfg     *config.Config
+	mu      sync.Mutex
+	started bool
+	closed  bool
+}
+
+// New creates a dashboard writing to stdout
+func New(cfg *config.Config) *Dashboard {
+	width := 80
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w >= 40 {
+		width = w
+	}
+	return &Dashboard{
+		Out:   os.Stdout,
+		Width: width,
+		Color: os.Getenv("NO_COLOR") == "",
+		cfg:   cfg,
+	}
+}
+
+// SetConfig replaces the configuration the frames are drawn with: the
+// title, indicator periods and threshold bands of the symbol shown
+func (d *Dashboard) SetConfig(cfg *config.Config) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.cfg = cfg
+}
+
+// Draw replaces the screen with a frame of s. The first call switches to
+// the alternate screen so the previous terminal content is restored on Close.
+func (d *Dashboard) Draw(s State) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return nil
+	}
+
+	var b strings.Builder
+	if !d.started {
+		b.WriteString(altScreen + hideCursor)
+		d.started = true
+	}
+	b.WriteString(clearHome)
+	b.WriteString(d.Frame(s, time.Now()))
+
+	_, err := io.WriteString(d.Out, b.String())
+	return err
+}
+
+// Close leaves the alternate screen and shows the cursor again
+func (d *Dashboard) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed || !d.started {
+		d.closed = true
+		return nil
+	}
+	d.closed = true
+
+	_, err := io.WriteString(d.Out, showCursor+mainScreen)
+	return err
+}
+
+// Frame renders s as plain lines without cursor control
+func (d *Dashboard) Frame(s State, now time.Time) string {
+	var b strings.Builder
+	line := func(text string) {
+		b.WriteString(text)
+		b.WriteString("\n")
+	}
+	sep := d.paint(dim, strings.Repeat("─", d.Width))
+	chartWidth := max(10, d.Width-labelWidth)
+	cfg := d.cfg
+
+	line(d.paint(bold, i18n.T("tui.title", cfg.Symbol, cfg.Interval)) + "  " + d.paint(dim, i18n.Time(now)))
+	line(sep)
+
+	res := s.Result
+	if res.Time.IsZero() {
+		line(i18n.T("tui.waiting"))
+	} else {
+		// Price with change and chart
+		change := fmt.Sprintf("↑ %+.2f (%+.2f%%)", res.Change, res.ChangePercent)
+		color := green
+		if res.Change < 0 {
+			change = fmt.Sprintf("↓ %+.2f (%+.2f%%)", res.Change, res.ChangePercent)
+			color = red
+		}
+		line(d.paint(bold, i18n.T("tui.price", res.Price)) + "  " + d.paint(color, change))
+
+		closes := make([]float64, len(s.Candles))
+		highs := make([]float64, len(s.Candles))
+		lows := make([]float64, len(s.Candles))
+		for i, c := range s.Candles {
+			closes[i] = c.Close
+			highs[i] = c.High
+			lows[i] = c.Low
+		}
+
+		recent := tail(closes, chartWidth)
+		lo, hi := bounds(recent)
+		for i, row := range Chart(recent, chartWidth, priceRows, lo, hi) {
+			label := ""
+			switch i {
+			case 0:
+				label = fmt.Sprintf(" %.2f", hi)
+			case priceRows - 1:
+				label = fmt.Sprintf(" %.2f", lo)
+			}
+			line(d.paint(cyan, row) + d.paint(dim, label))
+		}
+		line("")
+
+		// RSI pane on a fixed 0-100 scale
+		line(i18n.T("tui.rsi", res.Indicators.RSIPeriod, res.Indicators.RSI) + " " + rsiZone(cfg, res.Indicators.RSI))
+		// Panes are left out while the history is too short
+		if rsi, err := indicators.RSI(closes, cfg.RSIPeriod); err == nil {
+			rsi = rsi[indicators.RSIValidFrom(cfg.RSIPeriod):]
+			for i, row := range Chart(rsi, chartWidth, rsiRows, 0, 100) {
+				label := ""
+				switch i {
+				case 0:
+					label = fmt.Sprintf(" 100 · %.0f", cfg.RSISellThreshold)
+				case rsiRows - 1:
+					label = fmt.Sprintf(" 0 · %.0f", cfg.RSIBuyLower)
+				}
+				line(d.paint(yellow, row) + d.paint(dim, label))
+			}
+		}
+		line("")
+
+		// MACD histogram pane, positive bars above the zero line
+		line(i18n.T("tui.macd", res.Indicators.MACDHist))
+		if _, _, hist, err := indicators.MACDPeriods(closes, cfg.MACDFastPeriod, cfg.MACDSlowPeriod, cfg.MACDSignalPeriod); err == nil {
+			hist = hist[indicators.MACDValidFrom(cfg.MACDFastPeriod, cfg.MACDSlowPeriod, cfg.MACDSignalPeriod):]
+			rows := Bars(hist, chartWidth, macdRows)
+			for i, row := range rows {
+				color := green
+				if i >= len(rows)/2 {
+					color = red
+				}
+				line(d.paint(color, row))
+			}
+		}
+		line("")
+
+		line(i18n.T("tui.atr", res.Indicators.ATRPeriod, res.Indicators.ATR))
+		if atr, err := indicators.ATR(highs, lows, closes, cfg.ATRPeriod); err == nil {
+			atr = atr[indicators.ATRValidFrom(cfg.ATRPeriod):]
+			line(d.paint(cyan, Sparkline(atr, chartWidth)))
+		}
+		line(sep)
+
+		// Signal and reasons
+		line(d.paint(bold, i18n.T("tui.signal", d.signal(res.Signal))))
+		for _, r := range s.Reasons {
+			line("  " + strings.TrimSpace(r))
+		}
+	}
+	line(sep)
+
+	// Recent signal transitions, newest first
+	line(i18n.T("tui.history"))
+	if len(s.History) == 0 {
+		line(d.paint(dim, i18n.T("tui.no_history")))
+	}
+	for i := len(s.History) - 1; i >= 0 && i >= len(s.History)-historySize; i-- {
+		h := s.History[i]
+		prev := string(h.Previous)
+		if prev == "" {
+			prev = "—"
+		}
+		line(fmt.Sprintf("   %s  %s → %s  @ %.2f", i18n.Time(h.Time), prev, d.signal(h.Signal), h.Price))
+	}
+	line(sep)
+
+	// Fetch status
+	switch {
+	case s.Fetch.LastError != "":
+		line(d.paint(red, i18n.T("tui.fetch_failed", i18n.Time(s.Fetch.LastAttempt), s.Fetch.LastError)))
+	case !s.Fetch.LastSuccess.IsZero():
+		line(i18n.T("tui.fetch_ok", i18n.Time(s.Fetch.LastSuccess)))
+	}
+	line(d.paint(dim, i18n.T("tui.footer")))
+
+	return b.String()
+}
+
+// signal returns the colored signal name
+func (d *Dashboard) signal(sig strategy.Signal) string {
+	switch sig {
+	case strategy.BUY:
+		return d.paint(green, string(sig))
+	case strategy.SELL:
+		return d.paint(red, string(sig))
+	}
+	return d.paint(yellow, string(sig))
+}
+
+// rsiZone labels the RSI value with the console zone markers
+func rsiZone(cfg *config.Config, rsi float64) string {
+	switch {
+	case rsi < cfg.RSIBuyLower:
+		return strings.TrimSpace(i18n.T("analysis.rsi_oversold"))
+	case rsi > cfg.RSISellThreshold:
+		return strings.TrimSpace(i18n.T("analysis.rsi_overbought"))
+	case rsi > cfg.RSIBuyLower && rsi < cfg.RSIBuyUpper:
+		return strings.TrimSpace(i18n.T("analysis.rsi_buy_zone"))
+	}
+	return ""
+}
+
+func (d *Dashboard) paint(color, text string) string {
+	if !d.Color || text == "" {
+		return text
+	}
+	return color + text + reset
+}
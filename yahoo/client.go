@@ -1,12 +1,15 @@
 package yahoo
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"time"
 
+	"gold-analyzer/metrics"
 	"gold-analyzer/model"
 )
 
@@ -27,7 +30,19 @@ type response struct {
 	} `json:"chart"`
 }
 
-func FetchCandles(symbol, interval, rangeVal string) ([]model.Candle, error) {
+// FetchCandles downloads candles for symbol and records fetch metrics.
+// Cancelling ctx aborts the request and any retry wait.
+func FetchCandles(ctx context.Context, symbol, interval, rangeVal string) ([]model.Candle, error) {
+	start := time.Now()
+	candles, err := fetchCandles(ctx, symbol, interval, rangeVal)
+	metrics.FetchDuration.Observe(metrics.Since(start), symbol)
+	if err != nil && ctx.Err() == nil {
+		metrics.FetchErrors.Inc(symbol)
+	}
+	return candles, err
+}
+
+func fetchCandles(ctx context.Context, symbol, interval, rangeVal string) ([]model.Candle, error) {
 	url := "https://query1.finance.yahoo.com/v8/finance/chart/" +
 		symbol + "?interval=" + interval + "&range=" + rangeVal
 
@@ -36,7 +51,12 @@ func FetchCandles(symbol, interval, rangeVal string) ([]model.Candle, error) {
 
 	// Retry logic with exponential backoff
 	for attempt := 0; attempt < 5; attempt++ {
-		req, err := http.NewRequest("GET", url, nil)
+		if attempt > 0 {
+			metrics.FetchRetries.Inc(symbol)
+		}
+		metrics.FetchAttempts.Inc(symbol)
+
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -50,10 +70,16 @@ func FetchCandles(symbol, interval, rangeVal string) ([]model.Candle, error) {
 
 		resp, err := client.Do(req)
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
 			lastErr = err
 			waitTime := time.Duration(1<<uint(attempt)) * time.Second
-			fmt.Printf("Attempt %d failed, waiting %v before retry...\n", attempt+1, waitTime)
-			time.Sleep(waitTime)
+			slog.Warn("fetch attempt failed, retrying",
+				"symbol", symbol, "attempt", attempt+1, "wait", waitTime, "error", err)
+			if err := sleep(ctx, waitTime); err != nil {
+				return nil, err
+			}
 			continue
 		}
 		defer resp.Body.Close()
@@ -66,9 +92,13 @@ func FetchCandles(symbol, interval, rangeVal string) ([]model.Candle, error) {
 
 		// Check for rate limiting
 		if resp.StatusCode == 429 {
+			metrics.FetchRateLimited.Inc(symbol)
 			waitTime := time.Duration(1<<uint(attempt)) * 2 * time.Second
-			fmt.Printf("Rate limited (429), waiting %v before retry...\n", waitTime)
-			time.Sleep(waitTime)
+			slog.Warn("rate limited (429), retrying",
+				"symbol", symbol, "attempt", attempt+1, "wait", waitTime)
+			if err := sleep(ctx, waitTime); err != nil {
+				return nil, err
+			}
 			continue
 		}
 
@@ -123,3 +153,15 @@ func FetchCandles(symbol, interval, rangeVal string) ([]model.Candle, error) {
 
 	return candles, nil
 }
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}